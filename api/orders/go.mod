@@ -0,0 +1,5 @@
+module api/orders
+
+go 1.24.0
+
+require github.com/lib/pq v1.11.2
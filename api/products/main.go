@@ -5,11 +5,14 @@ import (
 	"database/sql"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/lib/pq"
 )
@@ -22,6 +25,18 @@ type Product struct {
 
 var db *sql.DB
 
+// validateProduct checks a product before it is written, trimming its name.
+func validateProduct(product *Product) error {
+	product.Name = strings.TrimSpace(product.Name)
+	if product.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	if product.Price < 0 {
+		return errors.New("price must not be negative")
+	}
+	return nil
+}
+
 func generateID() string {
 	b := make([]byte, 8)
 	rand.Read(b)
@@ -57,6 +72,24 @@ func main() {
 		log.Fatalf("Failed to create products table: %v\n", err)
 	}
 
+	// Deleted products are kept for a retention period so deletes can be undone.
+	_, err = db.Exec("ALTER TABLE products ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP")
+	if err != nil {
+		log.Fatalf("Failed to add deleted_at column: %v\n", err)
+	}
+
+	// Existing products are dated to the migration, since their real creation time is unknown.
+	_, err = db.Exec(`
+		ALTER TABLE products ADD COLUMN IF NOT EXISTS created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc');
+		CREATE INDEX IF NOT EXISTS products_created_at_idx ON products (created_at DESC, id) WHERE deleted_at IS NULL;
+		CREATE INDEX IF NOT EXISTS products_price_idx ON products (price, id) WHERE deleted_at IS NULL;
+	`)
+	if err != nil {
+		log.Fatalf("Failed to add created_at column: %v\n", err)
+	}
+
+	startPurgeJob()
+
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("POST /products", createProduct)
@@ -64,6 +97,8 @@ func main() {
 	mux.HandleFunc("GET /products/{id}", getProductByID)
 	mux.HandleFunc("PUT /products/{id}", updateProduct)
 	mux.HandleFunc("DELETE /products/{id}", deleteProduct)
+	// Admin endpoints
+	mux.HandleFunc("POST /products/{id}/restore", restoreProduct)
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -81,11 +116,16 @@ func createProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := validateProduct(&product); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	if product.ID == "" {
 		product.ID = generateID()
 	}
 
-	_, err := db.Exec("INSERT INTO products (id, name, price) VALUES ($1, $2, $3)", product.ID, product.Name, product.Price)
+	_, err := db.Exec("INSERT INTO products (id, name, price, created_at) VALUES ($1, $2, $3, $4)", product.ID, product.Name, product.Price, time.Now().UTC())
 	if err != nil {
 		http.Error(w, fmt.Sprintf("failed to insert product: %v", err), http.StatusInternalServerError)
 		return
@@ -96,17 +136,43 @@ func createProduct(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(product)
 }
 
+// productOrders maps the orderBy parameter of GET /products to an ORDER BY clause.
+var productOrders = map[string]string{
+	"newest": "created_at DESC, id",
+	"price":  "price, id",
+}
+
+// getAllProducts lists products, or the products named by ids. Listings can be sorted
+// with orderBy (newest or price, cheapest first) and capped with limit.
 func getAllProducts(w http.ResponseWriter, r *http.Request) {
-	idsParam := r.URL.Query().Get("ids")
+	q := r.URL.Query()
 	var rows *sql.Rows
 	var err error
 
-	if idsParam != "" {
-		ids := strings.Split(idsParam, ",")
-		rows, err = db.Query("SELECT id, name, price FROM products WHERE id = ANY($1)", pq.Array(ids))
-	} else {
-		rows, err = db.Query("SELECT id, name, price FROM products")
+	query := "SELECT id, name, price FROM products WHERE deleted_at IS NULL"
+	var args []any
+	if idsParam := q.Get("ids"); idsParam != "" {
+		args = append(args, pq.Array(strings.Split(idsParam, ",")))
+		query += " AND id = ANY($1)"
+	}
+	if v := q.Get("orderBy"); v != "" {
+		order, ok := productOrders[v]
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown orderBy %q", v), http.StatusBadRequest)
+			return
+		}
+		query += " ORDER BY " + order
+	}
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			http.Error(w, "limit must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		args = append(args, limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
 	}
+	rows, err = db.Query(query, args...)
 
 	if err != nil {
 		http.Error(w, fmt.Sprintf("failed to query products: %v", err), http.StatusInternalServerError)
@@ -136,7 +202,7 @@ func getProductByID(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 
 	var product Product
-	err := db.QueryRow("SELECT id, name, price FROM products WHERE id = $1", id).Scan(&product.ID, &product.Name, &product.Price)
+	err := db.QueryRow("SELECT id, name, price FROM products WHERE id = $1 AND deleted_at IS NULL", id).Scan(&product.ID, &product.Name, &product.Price)
 	if err == sql.ErrNoRows {
 		http.Error(w, "product not found", http.StatusNotFound)
 		return
@@ -157,8 +223,12 @@ func updateProduct(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := validateProduct(&updatedProduct); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	res, err := db.Exec("UPDATE products SET name = $1, price = $2 WHERE id = $3", updatedProduct.Name, updatedProduct.Price, id)
+	res, err := db.Exec("UPDATE products SET name = $1, price = $2 WHERE id = $3 AND deleted_at IS NULL", updatedProduct.Name, updatedProduct.Price, id)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("failed to update product: %v", err), http.StatusInternalServerError)
 		return
@@ -183,7 +253,7 @@ func updateProduct(w http.ResponseWriter, r *http.Request) {
 func deleteProduct(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 
-	res, err := db.Exec("DELETE FROM products WHERE id = $1", id)
+	res, err := db.Exec("UPDATE products SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL", time.Now().UTC(), id)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("failed to delete product: %v", err), http.StatusInternalServerError)
 		return
@@ -202,3 +272,54 @@ func deleteProduct(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func restoreProduct(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var product Product
+	err := db.QueryRow("UPDATE products SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING id, name, price", id).
+		Scan(&product.ID, &product.Name, &product.Price)
+	if err == sql.ErrNoRows {
+		http.Error(w, "deleted product not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("failed to restore product: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// startPurgeJob permanently removes products that have been deleted for longer than
+// PURGE_RETENTION (default 30 days), checking every PURGE_INTERVAL (default 1 hour).
+func startPurgeJob() {
+	retention := durationFromEnv("PURGE_RETENTION", 30*24*time.Hour)
+	interval := durationFromEnv("PURGE_INTERVAL", time.Hour)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			res, err := db.Exec("DELETE FROM products WHERE deleted_at < $1", time.Now().UTC().Add(-retention))
+			if err != nil {
+				log.Printf("Failed to purge deleted products: %v\n", err)
+			} else if n, _ := res.RowsAffected(); n > 0 {
+				log.Printf("Purged %d deleted products\n", n)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Fatalf("Invalid %s %q: expected a positive duration such as 720h\n", name, v)
+	}
+	return d
+}
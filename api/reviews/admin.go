@@ -0,0 +1,33 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+)
+
+// roleAdmin is the role the gateway forwards in X-User-Roles for moderators.
+const roleAdmin = "admin"
+
+// gatewaySecret is shared with the gateway and the subgraphs through GATEWAY_SECRET.
+// Admin-only endpoints only trust X-User-Roles on requests that carry it in
+// X-Gateway-Secret, and refuse every request when it is not set.
+var gatewaySecret = os.Getenv("GATEWAY_SECRET")
+
+// requireAdmin wraps a handler so it only serves callers with the admin role.
+func requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		secret := r.Header.Get("X-Gateway-Secret")
+		if gatewaySecret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(gatewaySecret)) != 1 {
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return
+		}
+		if !slices.Contains(strings.Split(r.Header.Get("X-User-Roles"), ","), roleAdmin) {
+			http.Error(w, "admin role required", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"api/reviews/aspects"
+	"api/reviews/sentiment"
+
+	"github.com/lib/pq"
+)
+
+var (
+	aspectsConfig aspects.Config
+	extractor     *aspects.Extractor
+)
+
+// AspectStats summarises how a product's approved reviews talk about one aspect.
+type AspectStats struct {
+	Aspect         string          `json:"aspect"`
+	MentionCount   int             `json:"mentionCount"`
+	AverageRating  float64         `json:"averageRating"`
+	SentimentScore float64         `json:"sentimentScore"`
+	Sentiment      sentiment.Label `json:"sentiment"`
+}
+
+// ProductAspects lists the aspects mentioned in a product's reviews, most mentioned
+// first.
+type ProductAspects struct {
+	ProductID string        `json:"productId"`
+	Aspects   []AspectStats `json:"aspects"`
+}
+
+func createAspectsTable() error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS review_aspects (
+			review_id VARCHAR(255) NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+			aspect VARCHAR(255) NOT NULL,
+			sentiment_score DOUBLE PRECISION NOT NULL,
+			PRIMARY KEY (review_id, aspect)
+		);
+		CREATE INDEX IF NOT EXISTS review_aspects_aspect_idx ON review_aspects (aspect, review_id);
+	`)
+	return err
+}
+
+// replaceAspects stores the aspects mentioned in body as the aspects of a review,
+// dropping any found in an earlier version of it.
+func replaceAspects(tx *sql.Tx, reviewID, body string) error {
+	if _, err := tx.Exec("DELETE FROM review_aspects WHERE review_id = $1", reviewID); err != nil {
+		return fmt.Errorf("failed to clear review aspects: %v", err)
+	}
+	for _, m := range extractor.Extract(body) {
+		_, err := tx.Exec(
+			"INSERT INTO review_aspects (review_id, aspect, sentiment_score) VALUES ($1, $2, $3)",
+			reviewID, m.Aspect, m.Score,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert review aspect: %v", err)
+		}
+	}
+	return nil
+}
+
+// getReviewAspects returns the aspects mentioned in the approved reviews of each
+// requested product, in request order.
+func getReviewAspects(w http.ResponseWriter, r *http.Request) {
+	productIdsParam := r.URL.Query().Get("productIds")
+	if productIdsParam == "" {
+		http.Error(w, "productIds is required", http.StatusBadRequest)
+		return
+	}
+	productIds := strings.Split(productIdsParam, ",")
+
+	rows, err := db.Query(`
+		SELECT r.product_id, a.aspect, COUNT(*), AVG(r.rating)::float8, AVG(a.sentiment_score)
+		FROM review_aspects a
+		JOIN reviews r ON r.id = a.review_id
+		WHERE r.product_id = ANY($1) AND r.status = $2 AND r.deleted_at IS NULL
+		GROUP BY r.product_id, a.aspect
+		ORDER BY r.product_id, COUNT(*) DESC, a.aspect`, pq.Array(productIds), StatusApproved)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query review aspects: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	aspectsMap := make(map[string][]AspectStats)
+	for rows.Next() {
+		var productID string
+		var a AspectStats
+		if err := rows.Scan(&productID, &a.Aspect, &a.MentionCount, &a.AverageRating, &a.SentimentScore); err != nil {
+			http.Error(w, fmt.Sprintf("failed to scan review aspects: %v", err), http.StatusInternalServerError)
+			return
+		}
+		a.Sentiment = sentiment.LabelFor(a.SentimentScore)
+		aspectsMap[productID] = append(aspectsMap[productID], a)
+	}
+
+	aspectsList := make([]ProductAspects, 0, len(productIds))
+	for _, id := range productIds {
+		found := aspectsMap[id]
+		if found == nil {
+			found = []AspectStats{}
+		}
+		aspectsList = append(aspectsList, ProductAspects{ProductID: id, Aspects: found})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(aspectsList)
+}
+
+// backfillAspects extracts the aspects of every review, for reviews written before
+// aspects were tracked or after the aspects config has changed.
+func backfillAspects(args []string) error {
+	fs := flag.NewFlagSet("backfill-aspects", flag.ContinueOnError)
+	batchSize := fs.Int("batch", 500, "number of reviews to process per transaction")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	total, err := backfillReviews("", *batchSize, func(tx *sql.Tx, id, body string, rating int) error {
+		return replaceAspects(tx, id, body)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Aspect backfill complete: %d reviews processed\n", total)
+	return nil
+}
+
+// discoverAspects prints frequently mentioned words and phrases that are not yet in the
+// aspects config, as suggestions for extending it.
+func discoverAspects(args []string) error {
+	fs := flag.NewFlagSet("discover-aspects", flag.ContinueOnError)
+	productID := fs.String("product", "", "only consider the reviews of this product")
+	minReviews := fs.Int("min", 5, "minimum number of reviews mentioning a phrase")
+	top := fs.Int("top", 30, "maximum number of suggestions")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *minReviews < 1 {
+		return fmt.Errorf("-min must be a positive integer")
+	}
+	if *top < 1 {
+		return fmt.Errorf("-top must be a positive integer")
+	}
+
+	query := "SELECT body FROM reviews WHERE status = $1 AND deleted_at IS NULL"
+	queryArgs := []any{StatusApproved}
+	if *productID != "" {
+		query += " AND product_id = $2"
+		queryArgs = append(queryArgs, *productID)
+	}
+
+	rows, err := db.Query(query, queryArgs...)
+	if err != nil {
+		return fmt.Errorf("failed to query reviews: %v", err)
+	}
+	defer rows.Close()
+
+	var bodies []string
+	for rows.Next() {
+		var body string
+		if err := rows.Scan(&body); err != nil {
+			return fmt.Errorf("failed to scan review: %v", err)
+		}
+		bodies = append(bodies, body)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to query reviews: %v", err)
+	}
+
+	for _, c := range aspects.Discover(aspectsConfig, bodies, *minReviews, *top) {
+		fmt.Printf("%6d  %s\n", c.Reviews, c.Phrase)
+	}
+	return nil
+}
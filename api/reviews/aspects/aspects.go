@@ -0,0 +1,140 @@
+// Package aspects finds the product features, such as the battery or the keys, that a
+// review talks about and how the reviewer feels about each of them.
+package aspects
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"api/reviews/sentiment"
+)
+
+// Config maps each aspect name to the words and phrases that mention it. Phrases are
+// matched word by word after lowercasing, so plurals must be listed separately.
+type Config struct {
+	Aspects map[string][]string `json:"aspects"`
+}
+
+// DefaultConfig covers features reviewers commonly mention across the catalog.
+func DefaultConfig() Config {
+	return Config{Aspects: map[string][]string{
+		"battery":          {"battery", "batteries", "battery life", "charge", "charging", "charger"},
+		"screen":           {"screen", "screens", "display", "displays", "monitor"},
+		"keys":             {"key", "keys", "keyboard", "keycaps", "switches", "typing"},
+		"sound":            {"sound", "audio", "speaker", "speakers", "volume", "bass", "noise"},
+		"price":            {"price", "priced", "cost", "value", "money", "expensive", "cheap"},
+		"build quality":    {"build", "build quality", "quality", "material", "materials", "plastic", "construction"},
+		"size":             {"size", "sizes", "fit", "fits", "small", "large", "tight", "loose"},
+		"comfort":          {"comfort", "comfortable", "uncomfortable", "ergonomic", "ergonomics"},
+		"design":           {"design", "look", "looks", "color", "colour", "style"},
+		"performance":      {"performance", "speed", "fast", "slow", "lag", "laggy", "responsive"},
+		"shipping":         {"shipping", "delivery", "delivered", "arrived", "package", "packaging"},
+		"customer service": {"customer service", "support", "refund", "warranty", "return", "returns"},
+	}}
+}
+
+// LoadConfig reads a JSON config file that replaces the default aspects. An empty path
+// returns the defaults.
+func LoadConfig(path string) (Config, error) {
+	if path == "" {
+		return DefaultConfig(), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to open aspects config: %v", err)
+	}
+	defer f.Close()
+
+	var cfg Config
+	if err := json.NewDecoder(f).Decode(&cfg); err != nil {
+		return Config{}, fmt.Errorf("failed to decode aspects config: %v", err)
+	}
+	if len(cfg.Aspects) == 0 {
+		return Config{}, fmt.Errorf("aspects config lists no aspects")
+	}
+	return cfg, nil
+}
+
+// Mention is an aspect discussed in a review. Score is the average sentiment of the
+// sentences that mention it, from -1 to 1.
+type Mention struct {
+	Aspect string  `json:"aspect"`
+	Score  float64 `json:"score"`
+}
+
+// Extractor finds configured aspects in review text.
+type Extractor struct {
+	keywords []keyword
+}
+
+type keyword struct {
+	aspect string
+	words  []string
+}
+
+// NewExtractor builds an extractor for the aspects in cfg.
+func NewExtractor(cfg Config) *Extractor {
+	e := &Extractor{}
+	for aspect, phrases := range cfg.Aspects {
+		for _, phrase := range phrases {
+			if words := sentiment.Words(phrase); len(words) > 0 {
+				e.keywords = append(e.keywords, keyword{aspect: aspect, words: words})
+			}
+		}
+	}
+	return e
+}
+
+// Extract returns the aspects mentioned in text, sorted by name. Every clause that
+// mentions an aspect contributes its sentiment to that aspect's score, so "Great keys,
+// but the battery died" scores the keys well and the battery badly.
+func (e *Extractor) Extract(text string) []Mention {
+	totals := make(map[string]float64)
+	counts := make(map[string]int)
+
+	for _, sentence := range sentiment.Sentences(text) {
+		for _, clause := range strings.Split(sentence, ",") {
+			words := sentiment.Words(clause)
+			mentioned := e.match(words)
+			if len(mentioned) == 0 {
+				continue
+			}
+			score := sentiment.Analyze(clause).Score
+			for _, aspect := range mentioned {
+				totals[aspect] += score
+				counts[aspect]++
+			}
+		}
+	}
+
+	mentions := make([]Mention, 0, len(counts))
+	for aspect, n := range counts {
+		mentions = append(mentions, Mention{Aspect: aspect, Score: totals[aspect] / float64(n)})
+	}
+	slices.SortFunc(mentions, func(a, b Mention) int { return strings.Compare(a.Aspect, b.Aspect) })
+	return mentions
+}
+
+// match returns the distinct aspects whose keywords occur in words.
+func (e *Extractor) match(words []string) []string {
+	var found []string
+	for _, k := range e.keywords {
+		if !slices.Contains(found, k.aspect) && containsPhrase(words, k.words) {
+			found = append(found, k.aspect)
+		}
+	}
+	return found
+}
+
+func containsPhrase(words, phrase []string) bool {
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		if slices.Equal(words[i:i+len(phrase)], phrase) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package aspects
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	e := NewExtractor(DefaultConfig())
+
+	tests := []struct {
+		text string
+		want map[string]int // aspect to the sign of its score
+	}{
+		{"It arrived.", map[string]int{"shipping": 0}},
+		{"Nothing to say about it", map[string]int{}},
+		{"Great keys, but the battery died", map[string]int{"keys": 1, "battery": -1}},
+		{"The customer service was terrible", map[string]int{"customer service": -1}},
+	}
+	for _, tt := range tests {
+		got := e.Extract(tt.text)
+		if len(got) != len(tt.want) {
+			t.Errorf("Extract(%q) = %+v, want aspects %v", tt.text, got, tt.want)
+			continue
+		}
+		for _, m := range got {
+			sign, ok := tt.want[m.Aspect]
+			if !ok || (sign > 0 && m.Score <= 0) || (sign < 0 && m.Score >= 0) || (sign == 0 && m.Score != 0) {
+				t.Errorf("Extract(%q) = %+v, want aspects %v", tt.text, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	// "battery" is already a configured aspect, so it is never suggested.
+	texts := []string{
+		"The trackpad is great and the hinge is solid",
+		"My trackpad stopped working, hinge creaks, battery fine",
+		"Trackpad clicks loudly. Battery fine.",
+	}
+
+	tests := []struct {
+		name string
+		top  int
+		want []Candidate
+	}{
+		{"most frequent first", 10, []Candidate{{"trackpad", 3}, {"hinge", 2}}},
+		{"limited to top", 1, []Candidate{{"trackpad", 3}}},
+		{"no suggestions requested", 0, []Candidate{}},
+		{"negative top returns all", -1, []Candidate{{"trackpad", 3}, {"hinge", 2}}},
+	}
+	for _, tt := range tests {
+		got := Discover(DefaultConfig(), texts, 2, tt.top)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: Discover() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package aspects
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"api/reviews/sentiment"
+)
+
+// stopWords are common words that never make a useful aspect on their own.
+var stopWords = map[string]bool{
+	"a": true, "about": true, "after": true, "again": true, "all": true, "also": true,
+	"am": true, "an": true, "and": true, "any": true, "are": true, "as": true, "at": true,
+	"be": true, "been": true, "before": true, "bit": true, "both": true, "bought": true,
+	"but": true, "by": true, "can": true, "could": true, "day": true, "days": true,
+	"did": true, "do": true, "does": true, "even": true, "ever": true, "every": true,
+	"for": true, "from": true, "get": true, "got": true, "had": true, "has": true,
+	"have": true, "he": true, "her": true, "here": true, "him": true, "his": true,
+	"how": true, "i": true, "if": true, "in": true, "into": true, "is": true, "it": true,
+	"it's": true, "its": true, "just": true, "lot": true, "made": true, "make": true,
+	"me": true, "month": true, "months": true, "more": true, "most": true, "much": true,
+	"my": true, "no": true, "not": true, "now": true, "of": true, "off": true, "on": true,
+	"once": true, "one": true, "only": true, "or": true, "other": true, "our": true,
+	"out": true, "over": true, "product": true, "really": true, "she": true, "so": true,
+	"some": true, "still": true, "than": true, "that": true, "the": true, "their": true,
+	"them": true, "then": true, "there": true, "these": true, "they": true, "thing": true,
+	"this": true, "those": true, "time": true, "to": true, "too": true, "two": true,
+	"up": true, "use": true, "used": true, "using": true, "very": true, "was": true,
+	"way": true, "we": true, "week": true, "weeks": true, "well": true, "were": true,
+	"what": true, "when": true, "which": true, "while": true, "who": true, "will": true,
+	"with": true, "would": true, "year": true, "years": true, "you": true, "your": true,
+}
+
+// Candidate is a word or two-word phrase that appears in many reviews.
+type Candidate struct {
+	Phrase  string `json:"phrase"`
+	Reviews int    `json:"reviews"`
+}
+
+// Discover suggests phrases worth adding to the aspects config: words and two-word
+// phrases mentioned in at least minReviews of texts that are not already configured.
+// Without a part-of-speech tagger it approximates noun phrases by dropping stop words,
+// opinion words and numbers. At most top candidates are returned, most frequent first;
+// a negative top returns them all.
+func Discover(cfg Config, texts []string, minReviews, top int) []Candidate {
+	known := make(map[string]bool)
+	for _, phrases := range cfg.Aspects {
+		for _, phrase := range phrases {
+			known[strings.Join(sentiment.Words(phrase), " ")] = true
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, text := range texts {
+		seen := make(map[string]bool)
+		for _, sentence := range sentiment.Sentences(text) {
+			for _, clause := range strings.Split(sentence, ",") {
+				var prev string
+				for _, w := range sentiment.Words(clause) {
+					if !isContentWord(w) {
+						prev = ""
+						continue
+					}
+					seen[w] = true
+					if prev != "" {
+						seen[prev+" "+w] = true
+					}
+					prev = w
+				}
+			}
+		}
+		for phrase := range seen {
+			counts[phrase]++
+		}
+	}
+
+	var candidates []Candidate
+	for phrase, n := range counts {
+		if n >= minReviews && !known[phrase] {
+			candidates = append(candidates, Candidate{Phrase: phrase, Reviews: n})
+		}
+	}
+	slices.SortFunc(candidates, func(a, b Candidate) int {
+		return cmp.Or(cmp.Compare(b.Reviews, a.Reviews), strings.Compare(a.Phrase, b.Phrase))
+	})
+	if top >= 0 && len(candidates) > top {
+		candidates = candidates[:top]
+	}
+	return candidates
+}
+
+func isContentWord(w string) bool {
+	if len(w) < 3 || stopWords[w] || sentiment.IsOpinionWord(w) || strings.HasSuffix(w, "n't") {
+		return false
+	}
+	return strings.IndexFunc(w, func(r rune) bool { return r < 'a' || r > 'z' }) < 0
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+)
+
+// commands are maintenance tasks run with `go run . <command> [flags]` instead of
+// starting the server. They run after the schema migrations.
+var commands = map[string]func(args []string) error{
+	"backfill-aspects":   backfillAspects,
+	"backfill-sentiment": backfillSentiment,
+	"discover-aspects":   discoverAspects,
+	"reconcile-stats":    reconcileRatingStats,
+	"scan-duplicates":    scanDuplicates,
+	"summarize":          summarizeReviews,
+}
+
+// runCommand runs the maintenance command named by args[0].
+func runCommand(args []string) {
+	run, ok := commands[args[0]]
+	if !ok {
+		names := make([]string, 0, len(commands))
+		for name := range commands {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		log.Fatalf("Unknown command %q, expected one of: %s\n", args[0], strings.Join(names, ", "))
+	}
+
+	if err := run(args[1:]); err != nil {
+		log.Fatalf("%s failed: %v\n", args[0], err)
+	}
+}
+
+// backfillReviews calls process for every review matching the pending condition, or
+// for every review when pending is empty, committing after each batch so progress is
+// kept if the command is interrupted. It returns the number of reviews processed.
+func backfillReviews(pending string, batchSize int, process func(tx *sql.Tx, id, body string, rating int) error) (int, error) {
+	condition := "id > $1"
+	if pending != "" {
+		condition = pending + " AND " + condition
+	}
+
+	lastID, total := "", 0
+	for {
+		n, next, err := backfillBatch(condition, lastID, batchSize, process)
+		if err != nil {
+			return total, err
+		}
+		if n == 0 {
+			return total, nil
+		}
+		total += n
+		lastID = next
+		log.Printf("Processed %d reviews\n", total)
+	}
+}
+
+// backfillBatch processes up to limit reviews after lastID in one transaction and
+// returns how many were processed and the last ID seen.
+func backfillBatch(condition, lastID string, limit int, process func(tx *sql.Tx, id, body string, rating int) error) (int, string, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT id, body, rating FROM reviews WHERE "+condition+" ORDER BY id LIMIT $2 FOR UPDATE", lastID, limit)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to query reviews: %v", err)
+	}
+
+	type row struct {
+		id, body string
+		rating   int
+	}
+	var batch []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.id, &r.body, &r.rating); err != nil {
+			rows.Close()
+			return 0, "", fmt.Errorf("failed to scan review: %v", err)
+		}
+		batch = append(batch, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, "", fmt.Errorf("failed to query reviews: %v", err)
+	}
+	if len(batch) == 0 {
+		return 0, lastID, nil
+	}
+
+	// Rows are read in full first because the connection can't run updates while a
+	// result set is still open.
+	for _, r := range batch {
+		if err := process(tx, r.id, r.body, r.rating); err != nil {
+			return 0, "", fmt.Errorf("failed to update review %s: %v", r.id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, "", fmt.Errorf("failed to commit batch: %v", err)
+	}
+	return len(batch), batch[len(batch)-1].id, nil
+}
@@ -0,0 +1,93 @@
+package dedup
+
+// Text is a review to cluster. Texts must be passed oldest first so that the original
+// of every cluster is its earliest review.
+type Text struct {
+	ID   string
+	Body string
+}
+
+// Member is a review found to near-duplicate another in its cluster. Similarity is its
+// similarity to the closest review it matched, which need not be the original.
+type Member struct {
+	ID         string
+	OriginalID string
+	Similarity float64
+}
+
+// Cluster groups texts whose similarity reaches threshold, directly or through a chain
+// of similar texts, and returns every text except each cluster's original.
+func Cluster(texts []Text, threshold float64) []Member {
+	fingerprints := make([]Fingerprint, len(texts))
+	ok := make([]bool, len(texts))
+	buckets := make(map[[2]int64][]int)
+	for i, t := range texts {
+		fingerprints[i], ok[i] = NewFingerprint(t.Body)
+		if !ok[i] {
+			continue
+		}
+		for b, key := range fingerprints[i].BandKeys {
+			bucket := [2]int64{int64(b), key}
+			buckets[bucket] = append(buckets[bucket], i)
+		}
+	}
+
+	// Union-find over the text indexes, always keeping the oldest text as the root.
+	parent := make([]int, len(texts))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	best := make([]float64, len(texts))
+	checked := make(map[[2]int]bool)
+	for _, indexes := range buckets {
+		for x := 0; x < len(indexes); x++ {
+			for y := x + 1; y < len(indexes); y++ {
+				a, b := indexes[x], indexes[y]
+				pair := [2]int{a, b}
+				if checked[pair] {
+					continue
+				}
+				checked[pair] = true
+
+				sim := fingerprints[a].Similarity(fingerprints[b])
+				if sim < threshold {
+					continue
+				}
+				best[a] = max(best[a], sim)
+				best[b] = max(best[b], sim)
+
+				ra, rb := find(a), find(b)
+				if ra == rb {
+					continue
+				}
+				if ra < rb {
+					parent[rb] = ra
+				} else {
+					parent[ra] = rb
+				}
+			}
+		}
+	}
+
+	var members []Member
+	for i := range texts {
+		root := find(i)
+		if root == i {
+			continue
+		}
+		members = append(members, Member{
+			ID:         texts[i].ID,
+			OriginalID: texts[root].ID,
+			Similarity: best[i],
+		})
+	}
+	return members
+}
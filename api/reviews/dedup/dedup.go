@@ -0,0 +1,124 @@
+// Package dedup detects near-duplicate review text. Texts are broken into overlapping
+// word shingles and summarised by a MinHash signature, whose bands let likely
+// duplicates be found by exact lookups instead of comparing every pair of reviews.
+package dedup
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// ShingleSize is the number of consecutive words in a shingle.
+	ShingleSize = 3
+
+	// MinWords is the shortest text considered. Shorter texts such as "Great!" are
+	// written independently by many people and would flood the clusters.
+	MinWords = 4
+
+	// Bands and RowsPerBand split the signature for locality-sensitive hashing. Two
+	// texts become candidates when all rows of any band agree, which makes pairs with
+	// a Jaccard similarity around 0.5 or more very likely to be found.
+	Bands       = 32
+	RowsPerBand = 4
+
+	signatureSize = Bands * RowsPerBand
+)
+
+// seeds holds one seed per MinHash function, generated deterministically so signatures
+// stay comparable across restarts.
+var seeds = func() [signatureSize]uint64 {
+	var s [signatureSize]uint64
+	state := uint64(0x5eed0fd0c5)
+	for i := range s {
+		state += 0x9e3779b97f4a7c15
+		s[i] = mix(state)
+	}
+	return s
+}()
+
+// Fingerprint is the shingle set of a text together with its LSH band keys.
+type Fingerprint struct {
+	shingles map[uint64]struct{}
+
+	// BandKeys holds one key per band; texts sharing any key are duplicate candidates.
+	BandKeys []int64
+}
+
+// NewFingerprint computes the fingerprint of text. It returns false when the text is
+// too short to judge.
+func NewFingerprint(text string) (Fingerprint, bool) {
+	words := normalize(text)
+	if len(words) < MinWords {
+		return Fingerprint{}, false
+	}
+
+	shingles := make(map[uint64]struct{})
+	for i := 0; i+ShingleSize <= len(words); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:i+ShingleSize], " ")))
+		shingles[h.Sum64()] = struct{}{}
+	}
+
+	var signature [signatureSize]uint64
+	for i := range signature {
+		signature[i] = ^uint64(0)
+	}
+	for s := range shingles {
+		for i, seed := range seeds {
+			if v := mix(s ^ seed); v < signature[i] {
+				signature[i] = v
+			}
+		}
+	}
+
+	keys := make([]int64, Bands)
+	buf := make([]byte, 8*RowsPerBand+1)
+	for b := range keys {
+		buf[0] = byte(b)
+		for r := 0; r < RowsPerBand; r++ {
+			binary.LittleEndian.PutUint64(buf[1+8*r:], signature[b*RowsPerBand+r])
+		}
+		h := fnv.New64a()
+		h.Write(buf)
+		keys[b] = int64(h.Sum64())
+	}
+
+	return Fingerprint{shingles: shingles, BandKeys: keys}, true
+}
+
+// Similarity returns the Jaccard similarity of the two texts' shingle sets, from 0
+// (nothing in common) to 1 (the same words in the same order).
+func (f Fingerprint) Similarity(other Fingerprint) float64 {
+	if len(f.shingles) == 0 || len(other.shingles) == 0 {
+		return 0
+	}
+	shared := 0
+	for s := range f.shingles {
+		if _, ok := other.shingles[s]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(f.shingles)+len(other.shingles)-shared)
+}
+
+// normalize lowercases text and splits it into words, ignoring punctuation, so that
+// "Amazing!!" and "amazing" compare equal.
+func normalize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// mix is the splitmix64 finalizer, used as a family of hash functions by combining its
+// input with a per-function seed.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
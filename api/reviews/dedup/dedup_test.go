@@ -0,0 +1,144 @@
+package dedup
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestNewFingerprint(t *testing.T) {
+	tests := []struct {
+		text string
+		ok   bool
+	}{
+		{"", false},
+		{"Great!", false},
+		{"Great value, works", false},
+		{"Great value, works well", true},
+	}
+	for _, tt := range tests {
+		f, ok := NewFingerprint(tt.text)
+		if ok != tt.ok {
+			t.Errorf("NewFingerprint(%q) ok = %v, want %v", tt.text, ok, tt.ok)
+		}
+		if ok && len(f.BandKeys) != Bands {
+			t.Errorf("NewFingerprint(%q) has %d band keys, want %d", tt.text, len(f.BandKeys), Bands)
+		}
+	}
+}
+
+func TestBandKeys(t *testing.T) {
+	fingerprint := func(text string) Fingerprint {
+		f, ok := NewFingerprint(text)
+		if !ok {
+			t.Fatalf("NewFingerprint(%q) is too short", text)
+		}
+		return f
+	}
+	shared := func(a, b Fingerprint) int {
+		n := 0
+		for i := range a.BandKeys {
+			if a.BandKeys[i] == b.BandKeys[i] {
+				n++
+			}
+		}
+		return n
+	}
+
+	original := fingerprint("The battery lasts all day and the keys feel great to type on")
+
+	// Band keys only depend on the words, not on case or punctuation.
+	if !slices.Equal(original.BandKeys, fingerprint("the battery lasts all day, and the KEYS feel great to type on!").BandKeys) {
+		t.Error("texts differing only in case and punctuation have different band keys")
+	}
+
+	tests := []struct {
+		name      string
+		text      string
+		candidate bool
+	}{
+		{"one word changed", "The battery lasts all day and the keys feel great to type with", true},
+		{"unrelated", "Shipping took three weeks and the box arrived crushed and soaked", false},
+	}
+	for _, tt := range tests {
+		n := shared(original, fingerprint(tt.text))
+		if candidate := n > 0; candidate != tt.candidate {
+			t.Errorf("%s: %d shared band keys, want candidate = %v", tt.name, n, tt.candidate)
+		}
+	}
+}
+
+// A band matches when all its MinHash rows agree, which happens with probability
+// J^RowsPerBand for texts with Jaccard similarity J, so the share of matching bands
+// estimates it.
+func TestBandKeysEstimateJaccard(t *testing.T) {
+	original, _ := NewFingerprint("The battery lasts all day and the keys feel great to type on")
+	for _, text := range []string{
+		"The battery lasts all day and the keys feel great to type with",
+		"The battery lasts all week and the keys feel great to type on",
+		"Honestly the battery lasts all day and the keys feel great to type on every day",
+	} {
+		other, _ := NewFingerprint(text)
+		matching := 0
+		for i := range original.BandKeys {
+			if original.BandKeys[i] == other.BandKeys[i] {
+				matching++
+			}
+		}
+
+		got := float64(matching) / Bands
+		want := math.Pow(original.Similarity(other), RowsPerBand)
+		if math.Abs(got-want) > 0.2 {
+			t.Errorf("%q: %.2f of bands match, want about %.2f", text, got, want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"one two three four", "one two three four", 1},
+		{"one two three four", "ONE two, three four!", 1},
+		// {one two three, two three four} and {one two three, two three five} share one
+		// of three distinct shingles.
+		{"one two three four", "one two three five", 1.0 / 3},
+		{"one two three four", "five six seven eight", 0},
+	}
+	for _, tt := range tests {
+		a, _ := NewFingerprint(tt.a)
+		b, _ := NewFingerprint(tt.b)
+		if got := a.Similarity(b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Similarity(%q, %q) = %f, want %f", tt.a, tt.b, got, tt.want)
+		}
+		if got := b.Similarity(a); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Similarity(%q, %q) = %f, want %f", tt.b, tt.a, got, tt.want)
+		}
+	}
+
+	var empty Fingerprint
+	a, _ := NewFingerprint("one two three four")
+	if got := empty.Similarity(a); got != 0 {
+		t.Errorf("Similarity with an empty fingerprint = %f, want 0", got)
+	}
+}
+
+func TestCluster(t *testing.T) {
+	texts := []Text{
+		{ID: "r1", Body: "The battery lasts all day and the keys feel great to type on"},
+		{ID: "r2", Body: "Shipping took three weeks and the box arrived crushed"},
+		{ID: "r3", Body: "The battery lasts all day and the keys feel great to type on!"},
+		{ID: "r4", Body: "Great!"},
+		{ID: "r5", Body: "the battery lasts all day and the keys feel great to type on"},
+	}
+
+	got := Cluster(texts, 0.8)
+	want := []Member{
+		{ID: "r3", OriginalID: "r1", Similarity: 1},
+		{ID: "r5", OriginalID: "r1", Similarity: 1},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Cluster() = %+v, want %+v", got, want)
+	}
+}
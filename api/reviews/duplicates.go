@@ -0,0 +1,374 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"api/reviews/dedup"
+
+	"github.com/lib/pq"
+)
+
+// duplicateThreshold is the shingle similarity from which two reviews count as near
+// duplicates. It is read from DUPLICATE_THRESHOLD.
+var duplicateThreshold = 0.7
+
+// Duplicate records that a review near-duplicates an earlier one. Reviews copied from
+// one another are grouped into a cluster named after its earliest review, the original.
+// Similarity is measured against the closest review the duplicate matched.
+type Duplicate struct {
+	ReviewID   string  `json:"reviewId"`
+	OriginalID string  `json:"originalId"`
+	Similarity float64 `json:"similarity"`
+}
+
+// DuplicateCluster is a group of near-identical reviews, oldest first.
+type DuplicateCluster struct {
+	ID               string            `json:"id"`
+	Size             int               `json:"size"`
+	DistinctUsers    int               `json:"distinctUsers"`
+	DistinctProducts int               `json:"distinctProducts"`
+	Reviews          []ClusteredReview `json:"reviews"`
+}
+
+// ClusteredReview is a review in a duplicate cluster. Similarity is unset for the
+// original.
+type ClusteredReview struct {
+	Review
+	Similarity *float64 `json:"similarity,omitempty"`
+}
+
+// createDuplicatesTables stores the LSH band keys of every review, used to look up
+// duplicate candidates, and the cluster each duplicate belongs to.
+func createDuplicatesTables() error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS review_fingerprint_bands (
+			review_id VARCHAR(255) NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+			band SMALLINT NOT NULL,
+			key BIGINT NOT NULL,
+			PRIMARY KEY (review_id, band)
+		);
+		CREATE INDEX IF NOT EXISTS review_fingerprint_bands_key_idx ON review_fingerprint_bands (band, key);
+		CREATE TABLE IF NOT EXISTS review_duplicates (
+			review_id VARCHAR(255) PRIMARY KEY REFERENCES reviews(id) ON DELETE CASCADE,
+			original_id VARCHAR(255) NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+			similarity DOUBLE PRECISION NOT NULL,
+			detected_at TIMESTAMP NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS review_duplicates_original_idx ON review_duplicates (original_id);
+	`)
+	return err
+}
+
+func loadDuplicateThreshold() {
+	v := os.Getenv("DUPLICATE_THRESHOLD")
+	if v == "" {
+		return
+	}
+	t, err := strconv.ParseFloat(v, 64)
+	if err != nil || t <= 0 || t > 1 {
+		log.Fatalf("Invalid DUPLICATE_THRESHOLD %q: expected a number between 0 and 1\n", v)
+	}
+	duplicateThreshold = t
+}
+
+// detectDuplicate fingerprints a new or edited review body and, if it near-duplicates
+// another live review, adds the review to that review's cluster.
+func detectDuplicate(tx *sql.Tx, reviewID, body string) error {
+	if _, err := tx.Exec("DELETE FROM review_fingerprint_bands WHERE review_id = $1", reviewID); err != nil {
+		return fmt.Errorf("failed to clear fingerprint: %v", err)
+	}
+	if _, err := tx.Exec("DELETE FROM review_duplicates WHERE review_id = $1", reviewID); err != nil {
+		return fmt.Errorf("failed to clear duplicate: %v", err)
+	}
+
+	fp, ok := dedup.NewFingerprint(body)
+	if !ok {
+		return nil
+	}
+
+	_, err := tx.Exec(`
+		INSERT INTO review_fingerprint_bands (review_id, band, key)
+		SELECT $1, band, key FROM unnest($2::bigint[]) WITH ORDINALITY AS t(key, band)`,
+		reviewID, pq.Array(fp.BandKeys),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store fingerprint: %v", err)
+	}
+
+	// Reviews that already belong to a cluster pass on its original, so a copy of a copy
+	// joins the first cluster instead of starting another.
+	rows, err := tx.Query(`
+		SELECT DISTINCT r.id, r.body, COALESCE(d.original_id, r.id)
+		FROM review_fingerprint_bands b
+		JOIN reviews r ON r.id = b.review_id
+		LEFT JOIN review_duplicates d ON d.review_id = r.id
+		WHERE (b.band, b.key) IN (SELECT band, key FROM unnest($1::bigint[]) WITH ORDINALITY AS t(key, band))
+			AND r.id <> $2 AND r.deleted_at IS NULL`,
+		pq.Array(fp.BandKeys), reviewID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to query duplicate candidates: %v", err)
+	}
+	defer rows.Close()
+
+	var best *Duplicate
+	for rows.Next() {
+		var candidateID, candidateBody, originalID string
+		if err := rows.Scan(&candidateID, &candidateBody, &originalID); err != nil {
+			return fmt.Errorf("failed to scan duplicate candidate: %v", err)
+		}
+		// The review is itself the original of this candidate's cluster.
+		if originalID == reviewID {
+			continue
+		}
+		other, ok := dedup.NewFingerprint(candidateBody)
+		if !ok {
+			continue
+		}
+		if sim := fp.Similarity(other); sim >= duplicateThreshold && (best == nil || sim > best.Similarity) {
+			best = &Duplicate{ReviewID: reviewID, OriginalID: originalID, Similarity: sim}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to query duplicate candidates: %v", err)
+	}
+	rows.Close()
+
+	if best == nil {
+		return nil
+	}
+	_, err = tx.Exec(
+		"INSERT INTO review_duplicates (review_id, original_id, similarity, detected_at) VALUES ($1, $2, $3, $4)",
+		best.ReviewID, best.OriginalID, best.Similarity, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record duplicate: %v", err)
+	}
+	return nil
+}
+
+// getDuplicates reports which of the requested reviews near-duplicate an earlier one.
+// Reviews that are not duplicates are left out.
+func getDuplicates(w http.ResponseWriter, r *http.Request) {
+	reviewIdsParam := r.URL.Query().Get("reviewIds")
+	if reviewIdsParam == "" {
+		http.Error(w, "reviewIds is required", http.StatusBadRequest)
+		return
+	}
+	reviewIds := strings.Split(reviewIdsParam, ",")
+
+	rows, err := db.Query(`
+		SELECT d.review_id, d.original_id, d.similarity
+		FROM review_duplicates d
+		JOIN reviews o ON o.id = d.original_id
+		WHERE d.review_id = ANY($1) AND o.deleted_at IS NULL`, pq.Array(reviewIds))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query duplicates: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	duplicates := []Duplicate{}
+	for rows.Next() {
+		var d Duplicate
+		if err := rows.Scan(&d.ReviewID, &d.OriginalID, &d.Similarity); err != nil {
+			http.Error(w, fmt.Sprintf("failed to scan duplicate: %v", err), http.StatusInternalServerError)
+			return
+		}
+		duplicates = append(duplicates, d)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(duplicates)
+}
+
+// getDuplicateClusters lists clusters of near-duplicate reviews of any status, largest
+// first, for moderators looking for templated spam.
+func getDuplicateClusters(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	limit := 20
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	minSize := 2
+	if v := q.Get("minSize"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 2 {
+			http.Error(w, "minSize must be an integer of at least 2", http.StatusBadRequest)
+			return
+		}
+		minSize = n
+	}
+
+	rows, err := db.Query(`
+		SELECT d.original_id
+		FROM review_duplicates d
+		JOIN reviews m ON m.id = d.review_id AND m.deleted_at IS NULL
+		JOIN reviews o ON o.id = d.original_id AND o.deleted_at IS NULL
+		GROUP BY d.original_id
+		HAVING COUNT(*) + 1 >= $1
+		ORDER BY COUNT(*) DESC, MAX(m.created_at) DESC, d.original_id
+		LIMIT $2`, minSize, limit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query duplicate clusters: %v", err), http.StatusInternalServerError)
+		return
+	}
+	var originalIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			http.Error(w, fmt.Sprintf("failed to scan duplicate cluster: %v", err), http.StatusInternalServerError)
+			return
+		}
+		originalIDs = append(originalIDs, id)
+	}
+	rows.Close()
+
+	clusters := make([]DuplicateCluster, 0, len(originalIDs))
+	if len(originalIDs) == 0 {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(clusters)
+		return
+	}
+
+	rows, err = db.Query(`
+		SELECT `+reviewColumns+`, COALESCE(original_id, id), similarity
+		FROM reviews
+		LEFT JOIN review_duplicates ON review_id = id
+		WHERE deleted_at IS NULL AND (id = ANY($1) OR original_id = ANY($1))
+		ORDER BY created_at, id`, pq.Array(originalIDs))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query clustered reviews: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	members := make(map[string][]ClusteredReview)
+	for rows.Next() {
+		var clusterID string
+		var similarity sql.NullFloat64
+		rev, err := scanReview(withExtraColumns(rows, &clusterID, &similarity))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to scan review: %v", err), http.StatusInternalServerError)
+			return
+		}
+		cr := ClusteredReview{Review: rev}
+		if similarity.Valid {
+			cr.Similarity = &similarity.Float64
+		}
+		members[clusterID] = append(members[clusterID], cr)
+	}
+
+	for _, id := range originalIDs {
+		users := make(map[string]bool)
+		products := make(map[string]bool)
+		for _, m := range members[id] {
+			users[m.UserID] = true
+			products[m.ProductID] = true
+		}
+		clusters = append(clusters, DuplicateCluster{
+			ID:               id,
+			Size:             len(members[id]),
+			DistinctUsers:    len(users),
+			DistinctProducts: len(products),
+			Reviews:          members[id],
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(clusters)
+}
+
+// scanDuplicates rebuilds the fingerprints and duplicate clusters of every live review
+// from scratch. It finds duplicates the insert-time check misses, such as two copies
+// written at the same moment, and applies a changed DUPLICATE_THRESHOLD to old reviews.
+func scanDuplicates(args []string) error {
+	fs := flag.NewFlagSet("scan-duplicates", flag.ContinueOnError)
+	threshold := fs.Float64("threshold", duplicateThreshold, "similarity from which reviews count as duplicates")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	rows, err := db.Query("SELECT id, body FROM reviews WHERE deleted_at IS NULL ORDER BY created_at, id")
+	if err != nil {
+		return fmt.Errorf("failed to query reviews: %v", err)
+	}
+	var texts []dedup.Text
+	for rows.Next() {
+		var t dedup.Text
+		if err := rows.Scan(&t.ID, &t.Body); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan review: %v", err)
+		}
+		texts = append(texts, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to query reviews: %v", err)
+	}
+
+	members := dedup.Cluster(texts, *threshold)
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM review_fingerprint_bands"); err != nil {
+		return fmt.Errorf("failed to clear fingerprints: %v", err)
+	}
+	insertBands, err := tx.Prepare("INSERT INTO review_fingerprint_bands (review_id, band, key) SELECT $1, band, key FROM unnest($2::bigint[]) WITH ORDINALITY AS t(key, band)")
+	if err != nil {
+		return fmt.Errorf("failed to prepare fingerprint insert: %v", err)
+	}
+	defer insertBands.Close()
+	for _, t := range texts {
+		fp, ok := dedup.NewFingerprint(t.Body)
+		if !ok {
+			continue
+		}
+		if _, err := insertBands.Exec(t.ID, pq.Array(fp.BandKeys)); err != nil {
+			return fmt.Errorf("failed to store fingerprint of %s: %v", t.ID, err)
+		}
+	}
+
+	if _, err := tx.Exec("DELETE FROM review_duplicates"); err != nil {
+		return fmt.Errorf("failed to clear duplicates: %v", err)
+	}
+	now := time.Now().UTC()
+	clusters := make(map[string]bool)
+	for _, m := range members {
+		_, err := tx.Exec(
+			"INSERT INTO review_duplicates (review_id, original_id, similarity, detected_at) VALUES ($1, $2, $3, $4)",
+			m.ID, m.OriginalID, m.Similarity, now,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record duplicate %s: %v", m.ID, err)
+		}
+		clusters[m.OriginalID] = true
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit duplicates: %v", err)
+	}
+
+	fmt.Printf("Duplicate scan complete: %d reviews scanned, %d duplicates in %d clusters\n", len(texts), len(members), len(clusters))
+	return nil
+}
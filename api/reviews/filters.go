@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"api/reviews/sentiment"
+)
+
+// reviewFilter collects the optional filter parameters accepted by GET /reviews and turns
+// them into SQL conditions.
+type reviewFilter struct {
+	conditions []string
+	args       []any
+}
+
+// add appends a condition whose single placeholder is written as %d and bound to arg.
+func (f *reviewFilter) add(condition string, arg any) {
+	f.args = append(f.args, arg)
+	f.conditions = append(f.conditions, fmt.Sprintf(condition, len(f.args)))
+}
+
+// parse reads status, minRating, maxRating, createdAfter, createdBefore, hasBody,
+// ratingMismatch, aspect and sentiment from the query string and appends the matching
+// conditions to f. Deleted reviews are never listed, and only approved ones are unless
+// another status, or "all", is requested.
+func (f *reviewFilter) parse(q url.Values) error {
+	f.conditions = append(f.conditions, "deleted_at IS NULL")
+
+	status := q.Get("status")
+	if status == "" {
+		status = StatusApproved
+	}
+	if status != "all" {
+		if !isValidStatus(status) {
+			return fmt.Errorf("unknown status %q", status)
+		}
+		f.add("status = $%d", status)
+	}
+
+	for _, p := range []struct{ param, condition string }{
+		{"minRating", "rating >= $%d"},
+		{"maxRating", "rating <= $%d"},
+	} {
+		if v := q.Get(p.param); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 || n > 5 {
+				return fmt.Errorf("%s must be between 1 and 5", p.param)
+			}
+			f.add(p.condition, n)
+		}
+	}
+
+	for _, p := range []struct{ param, condition string }{
+		{"createdAfter", "created_at > $%d"},
+		{"createdBefore", "created_at < $%d"},
+	} {
+		if v := q.Get(p.param); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return fmt.Errorf("%s must be an RFC 3339 timestamp", p.param)
+			}
+			f.add(p.condition, t.UTC())
+		}
+	}
+
+	for _, p := range []struct{ param, condition string }{
+		{"hasBody", "(btrim(body) <> '') = $%d"},
+		{"ratingMismatch", "rating_mismatch = $%d"},
+	} {
+		if v := q.Get(p.param); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("%s must be true or false", p.param)
+			}
+			f.add(p.condition, b)
+		}
+	}
+
+	if v := q.Get("aspect"); v != "" {
+		f.add("EXISTS (SELECT 1 FROM review_aspects a WHERE a.review_id = reviews.id AND a.aspect = $%d)", v)
+	}
+
+	if v := q.Get("sentiment"); v != "" {
+		label := sentiment.Label(strings.ToUpper(v))
+		if label != sentiment.Positive && label != sentiment.Neutral && label != sentiment.Negative {
+			return fmt.Errorf("unknown sentiment %q", v)
+		}
+		f.add("sentiment_label = $%d", label)
+	}
+
+	return nil
+}
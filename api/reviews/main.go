@@ -9,22 +9,71 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"api/reviews/aspects"
+	"api/reviews/screening"
+
 	"github.com/lib/pq"
 )
 
 type Review struct {
-	ID        string `json:"id"`
-	ProductID string `json:"productId"`
-	UserID    string `json:"userId"`
-	Body      string `json:"body"`
-	Rating    int    `json:"rating"`
-	CreatedAt string `json:"createdAt"`
+	ID               string `json:"id"`
+	ProductID        string `json:"productId"`
+	UserID           string `json:"userId"`
+	Body             string `json:"body"`
+	Rating           int    `json:"rating"`
+	CreatedAt        string `json:"createdAt"`
+	EditedAt         string `json:"editedAt,omitempty"`
+	Status           string `json:"status"`
+	ModerationReason string `json:"moderationReason,omitempty"`
+	Cursor           string `json:"cursor,omitempty"`
+
+	// Sentiment is nil until the review has been scored, see backfill-sentiment.
+	Sentiment *ReviewSentiment `json:"sentiment,omitempty"`
+
+	// ScreeningFlags is only reported in the responses to requests that screened the
+	// review: creating it or changing its body.
+	ScreeningFlags []screening.Finding `json:"screeningFlags,omitempty"`
+
+	createdAt time.Time
+}
+
+// reviewColumns lists the columns read by scanReview, in scan order.
+const reviewColumns = "id, product_id, user_id, body, rating, created_at, edited_at, status, moderation_reason, sentiment_score, sentiment_label, rating_mismatch"
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanReview(row rowScanner) (Review, error) {
+	var rev Review
+	var editedAt sql.NullTime
+	var reason sql.NullString
+	var sentimentScore sql.NullFloat64
+	var sentimentLabel sql.NullString
+	var ratingMismatch sql.NullBool
+	err := row.Scan(
+		&rev.ID, &rev.ProductID, &rev.UserID, &rev.Body, &rev.Rating, &rev.createdAt, &editedAt, &rev.Status, &reason,
+		&sentimentScore, &sentimentLabel, &ratingMismatch,
+	)
+	if err != nil {
+		return rev, err
+	}
+	rev.CreatedAt = rev.createdAt.Format(time.RFC3339)
+	if editedAt.Valid {
+		rev.EditedAt = editedAt.Time.Format(time.RFC3339)
+	}
+	rev.ModerationReason = reason.String
+	rev.Sentiment = scanSentiment(sentimentScore, sentimentLabel, ratingMismatch)
+	return rev, nil
 }
 
 var db *sql.DB
+var screener *screening.Pipeline
 
 func generateID() string {
 	b := make([]byte, 8)
@@ -64,16 +113,169 @@ func main() {
 		log.Fatalf("Failed to create reviews table: %v\n", err)
 	}
 
+	// Deleted reviews are kept for a retention period so deletes can be undone.
+	_, err = db.Exec("ALTER TABLE reviews ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP")
+	if err != nil {
+		log.Fatalf("Failed to add deleted_at column: %v\n", err)
+	}
+
+	if err = migrateModeration(); err != nil {
+		log.Fatalf("Failed to add moderation columns: %v\n", err)
+	}
+
+	if err = migrateUniqueReviews(); err != nil {
+		log.Fatalf("Failed to create reviews unique index: %v\n", err)
+	}
+
+	// Keyset pagination walks reviews per product and per user in (created_at, id) order.
+	_, err = db.Exec(`
+		CREATE INDEX IF NOT EXISTS reviews_product_created_idx ON reviews (product_id, created_at DESC, id DESC);
+		CREATE INDEX IF NOT EXISTS reviews_user_created_idx ON reviews (user_id, created_at DESC, id DESC);
+	`)
+	if err != nil {
+		log.Fatalf("Failed to create reviews indexes: %v\n", err)
+	}
+
+	if err = createVotesTable(); err != nil {
+		log.Fatalf("Failed to create review_votes table: %v\n", err)
+	}
+
+	if err = createScreeningFlagsTable(); err != nil {
+		log.Fatalf("Failed to create review_screening_flags table: %v\n", err)
+	}
+
+	if err = createResponsesTable(); err != nil {
+		log.Fatalf("Failed to create review_responses table: %v\n", err)
+	}
+
+	if err = migrateRevisions(); err != nil {
+		log.Fatalf("Failed to create review_revisions table: %v\n", err)
+	}
+
+	if err = migrateSearch(); err != nil {
+		log.Fatalf("Failed to create reviews search index: %v\n", err)
+	}
+
+	if err = migrateSentiment(); err != nil {
+		log.Fatalf("Failed to add sentiment columns: %v\n", err)
+	}
+
+	if err = createAspectsTable(); err != nil {
+		log.Fatalf("Failed to create review_aspects table: %v\n", err)
+	}
+
+	if err = createDuplicatesTables(); err != nil {
+		log.Fatalf("Failed to create duplicate detection tables: %v\n", err)
+	}
+	loadDuplicateThreshold()
+
+	if err = createReportsTable(); err != nil {
+		log.Fatalf("Failed to create review_reports table: %v\n", err)
+	}
+	loadReportHideThreshold()
+
+	if err = createRatingStatsTable(); err != nil {
+		log.Fatalf("Failed to create product_rating_stats table: %v\n", err)
+	}
+	loadRatingPriorWeight()
+
+	if err = createSummariesTable(); err != nil {
+		log.Fatalf("Failed to create review_summaries table: %v\n", err)
+	}
+
+	if err = createQuestionsTables(); err != nil {
+		log.Fatalf("Failed to create questions and answers tables: %v\n", err)
+	}
+
+	if err = migrateQuestionModeration(); err != nil {
+		log.Fatalf("Failed to add question moderation columns: %v\n", err)
+	}
+
+	screeningConfig, err := screening.LoadConfig(os.Getenv("SCREENING_CONFIG"))
+	if err != nil {
+		log.Fatalf("Failed to load screening config: %v\n", err)
+	}
+	screener = screening.NewPipelineFromConfig(screeningConfig)
+
+	aspectsConfig, err = aspects.LoadConfig(os.Getenv("ASPECTS_CONFIG"))
+	if err != nil {
+		log.Fatalf("Failed to load aspects config: %v\n", err)
+	}
+	extractor = aspects.NewExtractor(aspectsConfig)
+
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1:])
+		return
+	}
+
+	startPurgeJob()
+	startSummaryJob()
+
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("POST /reviews", createReview)
 	mux.HandleFunc("GET /reviews", getAllReviews)
+	mux.HandleFunc("GET /reviews/stats", getReviewStats)
+	mux.HandleFunc("GET /reviews/search", searchReviews)
+	mux.HandleFunc("GET /reviews/aspects", getReviewAspects)
+	mux.HandleFunc("GET /reviews/summaries", getReviewSummaries)
+	mux.HandleFunc("GET /reviews/top-products", getTopProducts)
+	// Duplicate detection
+	mux.HandleFunc("GET /reviews/duplicates", getDuplicates)
+	mux.HandleFunc("GET /reviews/duplicate-clusters", getDuplicateClusters)
 	mux.HandleFunc("GET /reviews/{id}", getReviewByID)
 	// Additional querying endpoints
 	mux.HandleFunc("GET /products/{productId}/reviews", getReviewsByProduct)
+	mux.HandleFunc("PUT /products/{productId}/reviews/{userId}", upsertReview)
 	mux.HandleFunc("GET /users/{userId}/reviews", getReviewsByUser)
 	mux.HandleFunc("PUT /reviews/{id}", updateReview)
 	mux.HandleFunc("DELETE /reviews/{id}", deleteReview)
+	mux.HandleFunc("POST /reviews/{id}/restore", requireAdmin(restoreReview))
+	// Helpfulness votes
+	mux.HandleFunc("GET /reviews/votes", getVoteCounts)
+	mux.HandleFunc("PUT /reviews/{id}/votes/{userId}", castVote)
+	mux.HandleFunc("DELETE /reviews/{id}/votes/{userId}", retractVote)
+	// Moderation
+	mux.HandleFunc("POST /reviews/{id}/approve", requireAdmin(moderateReview(StatusApproved)))
+	mux.HandleFunc("POST /reviews/{id}/reject", requireAdmin(moderateReview(StatusRejected)))
+	mux.HandleFunc("POST /reviews/{id}/hide", requireAdmin(moderateReview(StatusHidden)))
+	mux.HandleFunc("GET /reviews/{id}/screening", requireAdmin(getScreeningFlags))
+	// Reports
+	mux.HandleFunc("POST /reviews/{id}/reports", createReport)
+	mux.HandleFunc("GET /reviews/reports", requireAdmin(getReports))
+	mux.HandleFunc("POST /reviews/reports/{reportId}/resolve", requireAdmin(closeReport(ReportResolved)))
+	mux.HandleFunc("POST /reviews/reports/{reportId}/dismiss", requireAdmin(closeReport(ReportDismissed)))
+	// Edit history
+	mux.HandleFunc("GET /reviews/revisions", getRevisions)
+	mux.HandleFunc("GET /reviews/{id}/revisions", getReviewRevisions)
+	// Official responses
+	mux.HandleFunc("GET /reviews/responses", getResponses)
+	mux.HandleFunc("POST /reviews/{id}/response", createResponse)
+	mux.HandleFunc("GET /reviews/{id}/response", getResponse)
+	mux.HandleFunc("PUT /reviews/{id}/response", updateResponse)
+	mux.HandleFunc("DELETE /reviews/{id}/response", deleteResponse)
+
+	mux.HandleFunc("POST /products/{productId}/questions", createQuestion)
+	mux.HandleFunc("GET /products/{productId}/questions", getQuestionsByProduct)
+	mux.HandleFunc("GET /questions", getQuestions)
+	mux.HandleFunc("GET /questions/pending", requireAdmin(getPendingQuestions))
+	mux.HandleFunc("POST /questions/{id}/approve", requireAdmin(moderatePost("product_questions", "question", StatusApproved, writeQuestion)))
+	mux.HandleFunc("POST /questions/{id}/reject", requireAdmin(moderatePost("product_questions", "question", StatusRejected, writeQuestion)))
+	mux.HandleFunc("POST /questions/{id}/hide", requireAdmin(moderatePost("product_questions", "question", StatusHidden, writeQuestion)))
+	mux.HandleFunc("DELETE /questions/{id}", deleteQuestion)
+	mux.HandleFunc("POST /questions/{id}/restore", requireAdmin(restoreQuestion))
+	mux.HandleFunc("POST /questions/{id}/answers", createAnswer)
+	mux.HandleFunc("PUT /questions/{id}/accepted-answer", acceptAnswer)
+	mux.HandleFunc("DELETE /questions/{id}/accepted-answer", unacceptAnswer)
+	mux.HandleFunc("GET /answers", getAnswers)
+	mux.HandleFunc("GET /answers/pending", requireAdmin(getPendingAnswers))
+	mux.HandleFunc("POST /answers/{id}/approve", requireAdmin(moderatePost("product_answers", "answer", StatusApproved, writeAnswer)))
+	mux.HandleFunc("POST /answers/{id}/reject", requireAdmin(moderatePost("product_answers", "answer", StatusRejected, writeAnswer)))
+	mux.HandleFunc("POST /answers/{id}/hide", requireAdmin(moderatePost("product_answers", "answer", StatusHidden, writeAnswer)))
+	mux.HandleFunc("DELETE /answers/{id}", deleteAnswer)
+	mux.HandleFunc("POST /answers/{id}/restore", requireAdmin(restoreAnswer))
+	mux.HandleFunc("PUT /answers/{id}/upvotes/{userId}", upvoteAnswer)
+	mux.HandleFunc("DELETE /answers/{id}/upvotes/{userId}", retractUpvote)
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -91,19 +293,32 @@ func createReview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if review.ID == "" {
-		review.ID = generateID()
+	if review.ProductID == "" || review.UserID == "" {
+		http.Error(w, "productId and userId are required", http.StatusBadRequest)
+		return
 	}
-	if review.CreatedAt == "" {
-		review.CreatedAt = time.Now().Format(time.RFC3339)
+	if review.Rating < 1 || review.Rating > 5 {
+		http.Error(w, "rating must be between 1 and 5", http.StatusBadRequest)
+		return
 	}
 
-	_, err := db.Exec(
-		"INSERT INTO reviews (id, product_id, user_id, body, rating, created_at) VALUES ($1, $2, $3, $4, $5, $6)",
-		review.ID, review.ProductID, review.UserID, review.Body, review.Rating, review.CreatedAt,
-	)
+	tx, err := db.Begin()
 	if err != nil {
-		http.Error(w, fmt.Sprintf("failed to insert review: %v", err), http.StatusInternalServerError)
+		http.Error(w, fmt.Sprintf("failed to begin transaction: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	if err := insertReview(tx, &review); isReviewConflict(err) {
+		writeReviewConflict(w, review.ProductID, review.UserID)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to commit review: %v", err), http.StatusInternalServerError)
 		return
 	}
 
@@ -112,27 +327,136 @@ func createReview(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(review)
 }
 
+// reviewOrders maps the order query parameter to the ORDER BY clause it selects. The id
+// tiebreaker keeps the ordering stable between requests.
+var reviewOrders = map[string]string{
+	"newest":  "created_at DESC, id DESC",
+	"oldest":  "created_at ASC, id ASC",
+	"highest": "rating DESC, created_at DESC, id DESC",
+	"lowest":  "rating ASC, created_at DESC, id DESC",
+	// Net helpfulness: helpful votes minus not-helpful votes.
+	"most_helpful": "(SELECT COUNT(*) FILTER (WHERE v.helpful) - COUNT(*) FILTER (WHERE NOT v.helpful) FROM review_votes v WHERE v.review_id = reviews.id) DESC, created_at DESC, id DESC",
+}
+
+// insertReview screens a new review and stores it together with its original revision
+// and any screening findings. The ID and creation time are filled in when missing.
+func insertReview(tx *sql.Tx, review *Review) error {
+	if review.ID == "" {
+		review.ID = generateID()
+	}
+	if review.CreatedAt == "" {
+		review.CreatedAt = time.Now().Format(time.RFC3339)
+	}
+	// Screening decides whether the review is published straight away, waits for a
+	// moderator or is rejected.
+	result := screener.Screen(review.Body)
+	review.Status = screeningStatus(result.Verdict)
+	review.ModerationReason = result.Reasons()
+	review.ScreeningFlags = result.Findings
+	review.Sentiment = analyzeSentiment(review.Body, review.Rating)
+
+	_, err := tx.Exec(`
+		INSERT INTO reviews (id, product_id, user_id, body, rating, created_at, status, moderation_reason, sentiment_score, sentiment_label, rating_mismatch)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, $10, $11)`,
+		review.ID, review.ProductID, review.UserID, review.Body, review.Rating, review.CreatedAt, review.Status, review.ModerationReason,
+		review.Sentiment.Score, review.Sentiment.Label, review.Sentiment.RatingMismatch,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert review: %w", err)
+	}
+
+	if err := appendRevision(tx, review.ID, review.Body, review.Rating, review.UserID, review.CreatedAt); err != nil {
+		return err
+	}
+	if err := replaceAspects(tx, review.ID, review.Body); err != nil {
+		return err
+	}
+	if err := detectDuplicate(tx, review.ID, review.Body); err != nil {
+		return err
+	}
+	if err := insertScreeningFlags(tx, review.ID, result.Findings); err != nil {
+		return err
+	}
+	return syncRatingStats(tx, review.ProductID)
+}
+
 func getAllReviews(w http.ResponseWriter, r *http.Request) {
-	idsParam := r.URL.Query().Get("ids")
-	productIdsParam := r.URL.Query().Get("productIds")
-	userIdsParam := r.URL.Query().Get("userIds")
+	q := r.URL.Query()
 
-	var rows *sql.Rows
-	var err error
+	orderParam := q.Get("order")
+	if orderParam == "" {
+		orderParam = "newest"
+	}
+	order, ok := reviewOrders[orderParam]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown order %q", orderParam), http.StatusBadRequest)
+		return
+	}
+
+	limit := 0
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	perProductLimit := 0
+	if v := q.Get("perProductLimit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			http.Error(w, "perProductLimit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		if q.Get("productIds") == "" {
+			http.Error(w, "perProductLimit requires productIds", http.StatusBadRequest)
+			return
+		}
+		perProductLimit = n
+	}
+
+	var filter reviewFilter
+	for _, p := range []struct{ param, column string }{
+		{"ids", "id"},
+		{"productIds", "product_id"},
+		{"userIds", "user_id"},
+	} {
+		if v := q.Get(p.param); v != "" {
+			filter.add(p.column+" = ANY($%d)", pq.Array(strings.Split(v, ",")))
+		}
+	}
+	if err := filter.parse(q); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	if idsParam != "" {
-		ids := strings.Split(idsParam, ",")
-		rows, err = db.Query("SELECT id, product_id, user_id, body, rating, created_at FROM reviews WHERE id = ANY($1)", pq.Array(ids))
-	} else if productIdsParam != "" {
-		ids := strings.Split(productIdsParam, ",")
-		rows, err = db.Query("SELECT id, product_id, user_id, body, rating, created_at FROM reviews WHERE product_id = ANY($1)", pq.Array(ids))
-	} else if userIdsParam != "" {
-		ids := strings.Split(userIdsParam, ",")
-		rows, err = db.Query("SELECT id, product_id, user_id, body, rating, created_at FROM reviews WHERE user_id = ANY($1)", pq.Array(ids))
-	} else {
-		rows, err = db.Query("SELECT id, product_id, user_id, body, rating, created_at FROM reviews")
+	args := filter.args
+	where := ""
+	if len(filter.conditions) > 0 {
+		where = " WHERE " + strings.Join(filter.conditions, " AND ")
 	}
 
+	query := "SELECT " + reviewColumns + " FROM reviews" + where + " ORDER BY " + order
+	if perProductLimit > 0 {
+		// Rank each product's reviews separately so the limit applies per product
+		// rather than to the batch as a whole.
+		args = append(args, perProductLimit)
+		query = fmt.Sprintf(`
+			SELECT %[1]s FROM (
+				SELECT %[1]s, ROW_NUMBER() OVER (PARTITION BY product_id ORDER BY %[2]s) AS rn
+				FROM reviews%[3]s
+			) ranked
+			WHERE rn <= $%[4]d
+			ORDER BY product_id, rn`, reviewColumns, order, where, len(args))
+	}
+	if limit > 0 {
+		args = append(args, limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("failed to query reviews: %v", err), http.StatusInternalServerError)
 		return
@@ -141,13 +465,11 @@ func getAllReviews(w http.ResponseWriter, r *http.Request) {
 
 	var reviewList []Review
 	for rows.Next() {
-		var rev Review
-		var t time.Time
-		if err := rows.Scan(&rev.ID, &rev.ProductID, &rev.UserID, &rev.Body, &rev.Rating, &t); err != nil {
+		rev, err := scanReview(rows)
+		if err != nil {
 			http.Error(w, fmt.Sprintf("failed to scan review: %v", err), http.StatusInternalServerError)
 			return
 		}
-		rev.CreatedAt = t.Format(time.RFC3339)
 		reviewList = append(reviewList, rev)
 	}
 
@@ -162,10 +484,7 @@ func getAllReviews(w http.ResponseWriter, r *http.Request) {
 func getReviewByID(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 
-	var rev Review
-	var t time.Time
-	err := db.QueryRow("SELECT id, product_id, user_id, body, rating, created_at FROM reviews WHERE id = $1", id).
-		Scan(&rev.ID, &rev.ProductID, &rev.UserID, &rev.Body, &rev.Rating, &t)
+	rev, err := scanReview(db.QueryRow("SELECT "+reviewColumns+" FROM reviews WHERE id = $1 AND deleted_at IS NULL", id))
 	if err == sql.ErrNoRows {
 		http.Error(w, "review not found", http.StatusNotFound)
 		return
@@ -173,46 +492,57 @@ func getReviewByID(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("failed to query review: %v", err), http.StatusInternalServerError)
 		return
 	}
-	rev.CreatedAt = t.Format(time.RFC3339)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(rev)
 }
 
 func getReviewsByProduct(w http.ResponseWriter, r *http.Request) {
-	productId := r.PathValue("productId")
+	listReviewsPage(w, r, "product_id", r.PathValue("productId"))
+}
 
-	rows, err := db.Query("SELECT id, product_id, user_id, body, rating, created_at FROM reviews WHERE product_id = $1", productId)
+func getReviewsByUser(w http.ResponseWriter, r *http.Request) {
+	listReviewsPage(w, r, "user_id", r.PathValue("userId"))
+}
+
+// listReviewsPage lists the reviews whose column matches value, newest first. The optional
+// first/after and last/before query parameters select a page using keyset pagination
+// over (created_at, id); every review in the response carries the cursor for its position.
+func listReviewsPage(w http.ResponseWriter, r *http.Request, column, value string) {
+	page, err := parsePageParams(r.URL.Query())
 	if err != nil {
-		http.Error(w, fmt.Sprintf("failed to query reviews: %v", err), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	defer rows.Close()
 
-	var reviewList []Review
-	for rows.Next() {
-		var rev Review
-		var t time.Time
-		if err := rows.Scan(&rev.ID, &rev.ProductID, &rev.UserID, &rev.Body, &rev.Rating, &t); err != nil {
-			http.Error(w, fmt.Sprintf("failed to scan review: %v", err), http.StatusInternalServerError)
-			return
-		}
-		rev.CreatedAt = t.Format(time.RFC3339)
-		reviewList = append(reviewList, rev)
+	conditions := []string{column + " = $1", "status = '" + StatusApproved + "'", "deleted_at IS NULL"}
+	args := []any{value}
+	if page.after != nil {
+		args = append(args, page.after.createdAt, page.after.id)
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) < ($%d, $%d)", len(args)-1, len(args)))
 	}
-
-	if reviewList == nil {
-		reviewList = []Review{}
+	if page.before != nil {
+		args = append(args, page.before.createdAt, page.before.id)
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) > ($%d, $%d)", len(args)-1, len(args)))
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(reviewList)
-}
+	// Paging backwards walks the index in ascending order so the LIMIT keeps the rows
+	// closest to the cursor; they are flipped back to newest-first below.
+	order := "created_at DESC, id DESC"
+	limit := page.first
+	if page.last > 0 {
+		order = "created_at ASC, id ASC"
+		limit = page.last
+	}
 
-func getReviewsByUser(w http.ResponseWriter, r *http.Request) {
-	userId := r.PathValue("userId")
+	query := "SELECT " + reviewColumns + " FROM reviews WHERE " +
+		strings.Join(conditions, " AND ") + " ORDER BY " + order
+	if limit > 0 {
+		args = append(args, limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
 
-	rows, err := db.Query("SELECT id, product_id, user_id, body, rating, created_at FROM reviews WHERE user_id = $1", userId)
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("failed to query reviews: %v", err), http.StatusInternalServerError)
 		return
@@ -221,16 +551,19 @@ func getReviewsByUser(w http.ResponseWriter, r *http.Request) {
 
 	var reviewList []Review
 	for rows.Next() {
-		var rev Review
-		var t time.Time
-		if err := rows.Scan(&rev.ID, &rev.ProductID, &rev.UserID, &rev.Body, &rev.Rating, &t); err != nil {
+		rev, err := scanReview(rows)
+		if err != nil {
 			http.Error(w, fmt.Sprintf("failed to scan review: %v", err), http.StatusInternalServerError)
 			return
 		}
-		rev.CreatedAt = t.Format(time.RFC3339)
+		rev.Cursor = encodeCursor(rev.createdAt, rev.ID)
 		reviewList = append(reviewList, rev)
 	}
 
+	if page.last > 0 {
+		slices.Reverse(reviewList)
+	}
+
 	if reviewList == nil {
 		reviewList = []Review{}
 	}
@@ -242,51 +575,241 @@ func getReviewsByUser(w http.ResponseWriter, r *http.Request) {
 func updateReview(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 
-	var updatedReview Review
-	if err := json.NewDecoder(r.Body).Decode(&updatedReview); err != nil {
+	// Fields left out of the request body keep their current values. The editor
+	// defaults to the review's author.
+	var update struct {
+		Body     *string `json:"body"`
+		Rating   *int    `json:"rating"`
+		EditorID string  `json:"editorId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if update.Rating != nil && (*update.Rating < 1 || *update.Rating > 5) {
+		http.Error(w, "rating must be between 1 and 5", http.StatusBadRequest)
+		return
+	}
 
-	res, err := db.Exec("UPDATE reviews SET body = $1, rating = $2 WHERE id = $3", updatedReview.Body, updatedReview.Rating, id)
+	tx, err := db.Begin()
 	if err != nil {
-		http.Error(w, fmt.Sprintf("failed to update review: %v", err), http.StatusInternalServerError)
+		http.Error(w, fmt.Sprintf("failed to begin transaction: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	current, err := scanReview(tx.QueryRow("SELECT "+reviewColumns+" FROM reviews WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id))
+	if err == sql.ErrNoRows {
+		http.Error(w, "review not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query review: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	rowsAffected, err := res.RowsAffected()
+	body, rating := current.Body, current.Rating
+	if update.Body != nil {
+		body = *update.Body
+	}
+	if update.Rating != nil {
+		rating = *update.Rating
+	}
+
+	editorID := update.EditorID
+	if editorID == "" {
+		editorID = current.UserID
+	}
+
+	rev, err := editReview(tx, current, body, rating, editorID)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("failed to check rows affected: %v", err), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	if rowsAffected == 0 {
-		http.Error(w, "review not found", http.StatusNotFound)
+	if err := tx.Commit(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to commit review: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rev)
+}
+
+// editReview replaces the body and rating of current and records the change as a new
+// revision. current is returned unchanged when neither differs. A new body is screened
+// like a new review, so approved reviews can't be edited into content that would have
+// been held or rejected. The caller must hold a row lock on the review.
+func editReview(tx *sql.Tx, current Review, body string, rating int, editorID string) (Review, error) {
+	if body == current.Body && rating == current.Rating {
+		return current, nil
+	}
+	now := time.Now().UTC()
+
+	if err := recordRevision(tx, current, body, rating, editorID, now); err != nil {
+		return Review{}, err
+	}
+
+	status, reason := current.Status, current.ModerationReason
+	var result screening.Result
+	if body != current.Body {
+		result = screener.Screen(body)
+		// Clean text that leaves the status alone keeps the moderator's reason.
+		if s := editStatus(current.Status, result.Verdict); s != current.Status || result.Verdict != screening.Approve {
+			status, reason = s, result.Reasons()
+		}
+	}
+
+	// A new rating alone can still contradict the text, so sentiment is rescored on
+	// every edit.
+	scored := analyzeSentiment(body, rating)
+	rev, err := scanReview(tx.QueryRow(`
+		UPDATE reviews SET body = $1, rating = $2, edited_at = $3, sentiment_score = $4, sentiment_label = $5, rating_mismatch = $6,
+			status = $7, moderation_reason = NULLIF($8, '')
+		WHERE id = $9 RETURNING `+reviewColumns,
+		body, rating, now, scored.Score, scored.Label, scored.RatingMismatch, status, reason, current.ID,
+	))
+	if err != nil {
+		return Review{}, fmt.Errorf("failed to update review: %v", err)
+	}
+	if body != current.Body {
+		rev.ScreeningFlags = result.Findings
+		if err := insertScreeningFlags(tx, current.ID, result.Findings); err != nil {
+			return Review{}, err
+		}
+		if err := replaceAspects(tx, current.ID, body); err != nil {
+			return Review{}, err
+		}
+		if err := detectDuplicate(tx, current.ID, body); err != nil {
+			return Review{}, err
+		}
+	}
+	if err := syncRatingStats(tx, rev.ProductID); err != nil {
+		return Review{}, err
+	}
+	if err := pruneSummary(tx, rev.ProductID); err != nil {
+		return Review{}, err
+	}
+	return rev, nil
 }
 
 func deleteReview(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 
-	res, err := db.Exec("DELETE FROM reviews WHERE id = $1", id)
+	tx, err := db.Begin()
 	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to begin transaction: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	var productID string
+	err = tx.QueryRow("UPDATE reviews SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL RETURNING product_id", time.Now().UTC(), id).Scan(&productID)
+	if err == sql.ErrNoRows {
+		http.Error(w, "review not found", http.StatusNotFound)
+		return
+	} else if err != nil {
 		http.Error(w, fmt.Sprintf("failed to delete review: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	rowsAffected, err := res.RowsAffected()
+	if err := syncRatingStats(tx, productID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := pruneSummary(tx, productID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to commit delete: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// restoreReview brings back a deleted review that has not been purged yet. Admin only.
+func restoreReview(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	tx, err := db.Begin()
 	if err != nil {
-		http.Error(w, fmt.Sprintf("failed to check rows affected: %v", err), http.StatusInternalServerError)
+		http.Error(w, fmt.Sprintf("failed to begin transaction: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	rev, err := scanReview(tx.QueryRow("UPDATE reviews SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING "+reviewColumns, id))
+	if isReviewConflict(err) {
+		// The author has written another review of the product since this one was deleted.
+		tx.Rollback()
+		var productID, userID string
+		if err := db.QueryRow("SELECT product_id, user_id FROM reviews WHERE id = $1", id).Scan(&productID, &userID); err != nil {
+			http.Error(w, fmt.Sprintf("failed to query review: %v", err), http.StatusInternalServerError)
+			return
+		}
+		writeReviewConflict(w, productID, userID)
+		return
+	} else if err == sql.ErrNoRows {
+		http.Error(w, "deleted review not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("failed to restore review: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	if rowsAffected == 0 {
-		http.Error(w, "review not found", http.StatusNotFound)
+	if err := syncRatingStats(tx, rev.ProductID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to commit restore: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rev)
+}
+
+// startPurgeJob permanently removes reviews, questions and answers that have been
+// deleted for longer than PURGE_RETENTION (default 30 days), checking every
+// PURGE_INTERVAL (default 1 hour). Votes, responses, revisions, screening flags and
+// upvotes go with them.
+func startPurgeJob() {
+	retention := durationFromEnv("PURGE_RETENTION", 30*24*time.Hour)
+	interval := durationFromEnv("PURGE_INTERVAL", time.Hour)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			before := time.Now().UTC().Add(-retention)
+			purgeDeleted("reviews", "reviews", before)
+			purgeDeleted("product_questions", "questions", before)
+			purgeDeleted("product_answers", "answers", before)
+			<-ticker.C
+		}
+	}()
+}
+
+// purgeDeleted permanently removes the rows of table deleted before the given time.
+func purgeDeleted(table, noun string, before time.Time) {
+	res, err := db.Exec("DELETE FROM "+table+" WHERE deleted_at < $1", before)
+	if err != nil {
+		log.Printf("Failed to purge deleted %s: %v\n", noun, err)
+	} else if n, _ := res.RowsAffected(); n > 0 {
+		log.Printf("Purged %d deleted %s\n", n, noun)
+	}
+}
+
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Fatalf("Invalid %s %q: expected a positive duration such as 720h\n", name, v)
+	}
+	return d
 }
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Review moderation statuses. Only approved reviews are visible to the public; hidden
+// reviews were pulled down after publication but are kept for the record.
+const (
+	StatusPending  = "PENDING"
+	StatusApproved = "APPROVED"
+	StatusRejected = "REJECTED"
+	StatusHidden   = "HIDDEN"
+)
+
+func isValidStatus(status string) bool {
+	switch status {
+	case StatusPending, StatusApproved, StatusRejected, StatusHidden:
+		return true
+	}
+	return false
+}
+
+// migrateModeration adds the moderation columns to the reviews table. Reviews written
+// before moderation existed were already public, so they start out approved.
+func migrateModeration() error {
+	_, err := db.Exec(`
+		ALTER TABLE reviews ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'APPROVED';
+		ALTER TABLE reviews ALTER COLUMN status SET DEFAULT 'PENDING';
+		ALTER TABLE reviews ADD COLUMN IF NOT EXISTS moderation_reason TEXT;
+		ALTER TABLE reviews ADD COLUMN IF NOT EXISTS moderated_by VARCHAR(255);
+		ALTER TABLE reviews ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMP;
+		CREATE INDEX IF NOT EXISTS reviews_status_created_idx ON reviews (status, created_at);
+	`)
+	return err
+}
+
+// moderateReview returns a handler that moves a review to the given status. Rejecting or
+// hiding a review requires a reason, which is stored alongside the moderator's ID.
+func moderateReview(status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+
+		var decision struct {
+			ModeratorID string `json:"moderatorId"`
+			Reason      string `json:"reason"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&decision); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if decision.ModeratorID == "" {
+			http.Error(w, "moderatorId is required", http.StatusBadRequest)
+			return
+		}
+		if status != StatusApproved && decision.Reason == "" {
+			http.Error(w, "reason is required", http.StatusBadRequest)
+			return
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to begin transaction: %v", err), http.StatusInternalServerError)
+			return
+		}
+		defer tx.Rollback()
+
+		rev, err := scanReview(tx.QueryRow(
+			"UPDATE reviews SET status = $1, moderation_reason = NULLIF($2, ''), moderated_by = $3, moderated_at = $4 WHERE id = $5 AND deleted_at IS NULL RETURNING "+reviewColumns,
+			status, decision.Reason, decision.ModeratorID, time.Now().UTC(), id,
+		))
+		if err == sql.ErrNoRows {
+			http.Error(w, "review not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, fmt.Sprintf("failed to moderate review: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		if err := syncRatingStats(tx, rev.ProductID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := pruneSummary(tx, rev.ProductID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := tx.Commit(); err != nil {
+			http.Error(w, fmt.Sprintf("failed to commit moderation: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rev)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPageSize caps the number of reviews returned by a single paginated request.
+const maxPageSize = 1000
+
+// cursor identifies a review's position in the (created_at, id) keyset ordering.
+type cursor struct {
+	createdAt time.Time
+	id        string
+}
+
+type pageParams struct {
+	first  int
+	last   int
+	after  *cursor
+	before *cursor
+}
+
+// encodeCursor returns an opaque cursor for the review created at t with the given id.
+func encodeCursor(t time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(t.UTC().Format(time.RFC3339Nano) + "," + id))
+}
+
+func decodeCursor(s string) (*cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	ts, id, ok := strings.Cut(string(raw), ",")
+	if !ok || id == "" {
+		return nil, errors.New("invalid cursor")
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	return &cursor{createdAt: t, id: id}, nil
+}
+
+// parsePageParams reads first/after/last/before from the query string. Pages are
+// requested either forwards with first or backwards with last, never both.
+func parsePageParams(q url.Values) (pageParams, error) {
+	var p pageParams
+	var err error
+
+	if p.first, err = parsePageSize(q, "first"); err != nil {
+		return p, err
+	}
+	if p.last, err = parsePageSize(q, "last"); err != nil {
+		return p, err
+	}
+	if p.first > 0 && p.last > 0 {
+		return p, errors.New("first and last cannot be combined")
+	}
+
+	if v := q.Get("after"); v != "" {
+		if p.after, err = decodeCursor(v); err != nil {
+			return p, err
+		}
+	}
+	if v := q.Get("before"); v != "" {
+		if p.before, err = decodeCursor(v); err != nil {
+			return p, err
+		}
+	}
+
+	return p, nil
+}
+
+func parsePageSize(q url.Values, name string) (int, error) {
+	v := q.Get(name)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+	if n > maxPageSize {
+		n = maxPageSize
+	}
+	return n, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/base64"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		createdAt time.Time
+		id        string
+	}{
+		{time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), "1a2b3c4d5e6f7g8h"},
+		{time.Date(2024, 5, 1, 12, 0, 0, 123456789, time.UTC), "r_1"},
+		// Cursors are always in UTC.
+		{time.Date(2024, 5, 1, 14, 0, 0, 0, time.FixedZone("CEST", 2*60*60)), "r_2"},
+		// Only the first comma separates the time from the ID.
+		{time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), "a,b"},
+	}
+	for _, tt := range tests {
+		s := encodeCursor(tt.createdAt, tt.id)
+		c, err := decodeCursor(s)
+		if err != nil {
+			t.Errorf("decodeCursor(encodeCursor(%v, %q)) failed: %v", tt.createdAt, tt.id, err)
+			continue
+		}
+		if !c.createdAt.Equal(tt.createdAt) || c.id != tt.id {
+			t.Errorf("decodeCursor(encodeCursor(%v, %q)) = %v, %q", tt.createdAt, tt.id, c.createdAt, c.id)
+		}
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	for _, s := range []string{
+		"",
+		"not base64!",
+		encode("2024-05-01T12:00:00Z"),
+		encode("2024-05-01T12:00:00Z,"),
+		encode("yesterday,r_1"),
+	} {
+		if _, err := decodeCursor(s); err == nil {
+			t.Errorf("decodeCursor(%q) succeeded, want an error", s)
+		}
+	}
+}
+
+func TestParsePageParams(t *testing.T) {
+	after := encodeCursor(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), "r_1")
+	tests := []struct {
+		query       string
+		first, last int
+		after       bool
+		wantErr     bool
+	}{
+		{query: ""},
+		{query: "first=10", first: 10},
+		{query: "last=5", last: 5},
+		{query: "first=10&after=" + after, first: 10, after: true},
+		{query: "first=5000", first: maxPageSize},
+		{query: "first=10&last=5", wantErr: true},
+		{query: "first=-1", wantErr: true},
+		{query: "last=ten", wantErr: true},
+		{query: "after=bogus", wantErr: true},
+	}
+	for _, tt := range tests {
+		q, _ := url.ParseQuery(tt.query)
+		p, err := parsePageParams(q)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parsePageParams(%q) error = %v, want error %v", tt.query, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if p.first != tt.first || p.last != tt.last || (p.after != nil) != tt.after {
+			t.Errorf("parsePageParams(%q) = %+v", tt.query, p)
+		}
+	}
+}
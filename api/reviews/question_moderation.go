@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// migrateQuestionModeration adds the moderation columns to questions and answers. Posts
+// written before they were screened were already public, so they start out approved.
+func migrateQuestionModeration() error {
+	for _, table := range []string{"product_questions", "product_answers"} {
+		_, err := db.Exec(`
+			ALTER TABLE ` + table + ` ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'APPROVED';
+			ALTER TABLE ` + table + ` ALTER COLUMN status SET DEFAULT 'PENDING';
+			ALTER TABLE ` + table + ` ADD COLUMN IF NOT EXISTS moderation_reason TEXT;
+			ALTER TABLE ` + table + ` ADD COLUMN IF NOT EXISTS moderated_by VARCHAR(255);
+			ALTER TABLE ` + table + ` ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMP;
+			CREATE INDEX IF NOT EXISTS ` + table + `_status_created_idx ON ` + table + ` (status, created_at);
+		`)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// postStatusCondition returns the condition on the status of the question or answer
+// aliased as alias selected by the status query parameter: approved by default, another
+// status, or "all".
+func postStatusCondition(q url.Values, alias string) (string, error) {
+	status := q.Get("status")
+	if status == "" {
+		status = StatusApproved
+	}
+	if status == "all" {
+		return "TRUE", nil
+	}
+	if !isValidStatus(status) {
+		return "", fmt.Errorf("unknown status %q", status)
+	}
+	// The status is one of the constants, so it is safe to inline.
+	return alias + ".status = '" + status + "'", nil
+}
+
+// moderatePost returns a handler that moves the question or answer in table to the given
+// status, like moderateReview, and writes it with write.
+func moderatePost(table, noun, status string, write func(http.ResponseWriter, string, int)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+
+		var decision struct {
+			ModeratorID string `json:"moderatorId"`
+			Reason      string `json:"reason"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&decision); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if decision.ModeratorID == "" {
+			http.Error(w, "moderatorId is required", http.StatusBadRequest)
+			return
+		}
+		if status != StatusApproved && decision.Reason == "" {
+			http.Error(w, "reason is required", http.StatusBadRequest)
+			return
+		}
+
+		res, err := db.Exec(
+			"UPDATE "+table+" SET status = $1, moderation_reason = NULLIF($2, ''), moderated_by = $3, moderated_at = $4 WHERE id = $5 AND deleted_at IS NULL",
+			status, decision.Reason, decision.ModeratorID, time.Now().UTC(), id,
+		)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to moderate %s: %v", noun, err), http.StatusInternalServerError)
+			return
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to check rows affected: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		if rowsAffected == 0 {
+			http.Error(w, noun+" not found", http.StatusNotFound)
+			return
+		}
+
+		write(w, id, http.StatusOK)
+	}
+}
+
+// getPendingQuestions lists the questions awaiting moderation, oldest first.
+func getPendingQuestions(w http.ResponseWriter, r *http.Request) {
+	writePending(w, r, "SELECT "+questionColumns+" FROM product_questions q WHERE q.status = $1 AND q.deleted_at IS NULL ORDER BY q.created_at, q.id LIMIT $2", scanQuestion)
+}
+
+// getPendingAnswers lists the answers awaiting moderation, oldest first.
+func getPendingAnswers(w http.ResponseWriter, r *http.Request) {
+	writePending(w, r, "SELECT "+answerColumns+" FROM product_answers a WHERE a.status = $1 AND a.deleted_at IS NULL ORDER BY a.created_at, a.id LIMIT $2", scanAnswer)
+}
+
+// writePending runs a pending queue query, limited to the limit query parameter
+// (default 50), and writes the posts it returns.
+func writePending[T any](w http.ResponseWriter, r *http.Request, query string, scan func(rowScanner) (T, error)) {
+	limit := 50
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = min(n, maxPageSize)
+	}
+
+	rows, err := db.Query(query, StatusPending, limit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query pending posts: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	posts := []T{}
+	for rows.Next() {
+		post, err := scan(rows)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to scan pending post: %v", err), http.StatusInternalServerError)
+			return
+		}
+		posts = append(posts, post)
+	}
+	if err := rows.Err(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to query pending posts: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(posts)
+}
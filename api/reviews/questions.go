@@ -0,0 +1,678 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Question is a shopper's question about a product, answered by other shoppers.
+type Question struct {
+	ID               string `json:"id"`
+	ProductID        string `json:"productId"`
+	UserID           string `json:"userId"`
+	Body             string `json:"body"`
+	CreatedAt        string `json:"createdAt"`
+	Status           string `json:"status"`
+	ModerationReason string `json:"moderationReason,omitempty"`
+	AnswerCount      int    `json:"answerCount"`
+	AcceptedAnswerID string `json:"acceptedAnswerId,omitempty"`
+	Cursor           string `json:"cursor,omitempty"`
+
+	createdAt time.Time
+}
+
+// Answer is a reply to a question. The question's author can accept one answer.
+type Answer struct {
+	ID               string `json:"id"`
+	QuestionID       string `json:"questionId"`
+	UserID           string `json:"userId"`
+	Body             string `json:"body"`
+	CreatedAt        string `json:"createdAt"`
+	Status           string `json:"status"`
+	ModerationReason string `json:"moderationReason,omitempty"`
+	Accepted         bool   `json:"accepted"`
+	UpvoteCount      int    `json:"upvoteCount"`
+}
+
+// questionColumns lists the columns read by scanQuestion, in scan order. Queries select
+// them from product_questions aliased as q. Only published answers are counted.
+const questionColumns = `q.id, q.product_id, q.user_id, q.body, q.created_at, q.status, q.moderation_reason,
+	(SELECT COUNT(*) FROM product_answers a WHERE a.question_id = q.id AND a.status = '` + StatusApproved + `' AND a.deleted_at IS NULL),
+	(SELECT a.id FROM product_answers a WHERE a.question_id = q.id AND a.accepted AND a.status = '` + StatusApproved + `' AND a.deleted_at IS NULL)`
+
+// answerUpvotes counts the upvotes of the answer aliased as a.
+const answerUpvotes = "(SELECT COUNT(*) FROM answer_upvotes u WHERE u.answer_id = a.id)"
+
+// answerColumns lists the columns read by scanAnswer, in scan order. Queries select them
+// from product_answers aliased as a.
+const answerColumns = "a.id, a.question_id, a.user_id, a.body, a.created_at, a.status, a.moderation_reason, a.accepted, " + answerUpvotes
+
+// answerOrder lists a question's answers with the accepted one first, then the most
+// upvoted, then the oldest.
+const answerOrder = "a.accepted DESC, " + answerUpvotes + " DESC, a.created_at, a.id"
+
+func scanQuestion(row rowScanner) (Question, error) {
+	var q Question
+	var reason, accepted sql.NullString
+	if err := row.Scan(&q.ID, &q.ProductID, &q.UserID, &q.Body, &q.createdAt, &q.Status, &reason, &q.AnswerCount, &accepted); err != nil {
+		return q, err
+	}
+	q.CreatedAt = q.createdAt.Format(time.RFC3339)
+	q.ModerationReason = reason.String
+	q.AcceptedAnswerID = accepted.String
+	return q, nil
+}
+
+func scanAnswer(row rowScanner) (Answer, error) {
+	var a Answer
+	var createdAt time.Time
+	var reason sql.NullString
+	if err := row.Scan(&a.ID, &a.QuestionID, &a.UserID, &a.Body, &createdAt, &a.Status, &reason, &a.Accepted, &a.UpvoteCount); err != nil {
+		return a, err
+	}
+	a.CreatedAt = createdAt.Format(time.RFC3339)
+	a.ModerationReason = reason.String
+	return a, nil
+}
+
+// createQuestionsTables stores questions, their answers and the upvotes on answers.
+// Deleting a question deletes its answers, and at most one answer per question is
+// accepted. Like reviews, deleted questions and answers are kept until the purge job
+// removes them.
+func createQuestionsTables() error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS product_questions (
+			id VARCHAR(255) PRIMARY KEY,
+			product_id VARCHAR(255) NOT NULL,
+			user_id VARCHAR(255) NOT NULL,
+			body TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS product_questions_product_created_idx ON product_questions (product_id, created_at DESC, id DESC);
+
+		CREATE TABLE IF NOT EXISTS product_answers (
+			id VARCHAR(255) PRIMARY KEY,
+			question_id VARCHAR(255) NOT NULL REFERENCES product_questions(id) ON DELETE CASCADE,
+			user_id VARCHAR(255) NOT NULL,
+			body TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			accepted BOOLEAN NOT NULL DEFAULT FALSE
+		);
+		CREATE INDEX IF NOT EXISTS product_answers_question_idx ON product_answers (question_id);
+		CREATE UNIQUE INDEX IF NOT EXISTS product_answers_accepted_idx ON product_answers (question_id) WHERE accepted;
+
+		CREATE TABLE IF NOT EXISTS answer_upvotes (
+			answer_id VARCHAR(255) NOT NULL REFERENCES product_answers(id) ON DELETE CASCADE,
+			user_id VARCHAR(255) NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (answer_id, user_id)
+		);
+
+		ALTER TABLE product_questions ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+		ALTER TABLE product_answers ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+	`)
+	return err
+}
+
+// postInput is the request body for asking a question or answering one.
+type postInput struct {
+	UserID string `json:"userId"`
+	Body   string `json:"body"`
+}
+
+func decodePostInput(w http.ResponseWriter, r *http.Request) (postInput, bool) {
+	var input postInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return input, false
+	}
+	input.Body = strings.TrimSpace(input.Body)
+	if input.UserID == "" || input.Body == "" {
+		http.Error(w, "userId and body are required", http.StatusBadRequest)
+		return input, false
+	}
+	return input, true
+}
+
+// createQuestion screens a new question like a review: it is published straight away,
+// waits for a moderator or is rejected.
+func createQuestion(w http.ResponseWriter, r *http.Request) {
+	productID := r.PathValue("productId")
+
+	input, ok := decodePostInput(w, r)
+	if !ok {
+		return
+	}
+
+	result := screener.Screen(input.Body)
+	id := generateID()
+	_, err := db.Exec(`
+		INSERT INTO product_questions (id, product_id, user_id, body, created_at, status, moderation_reason)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''))`,
+		id, productID, input.UserID, input.Body, time.Now().UTC(), screeningStatus(result.Verdict), result.Reasons(),
+	)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to insert question: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeQuestion(w, id, http.StatusCreated)
+}
+
+// getQuestions returns the questions in ids. Unknown and deleted IDs are left out, and
+// so are questions that are not approved unless another status, or "all", is requested.
+func getQuestions(w http.ResponseWriter, r *http.Request) {
+	idsParam := r.URL.Query().Get("ids")
+	if idsParam == "" {
+		http.Error(w, "ids is required", http.StatusBadRequest)
+		return
+	}
+	status, err := postStatusCondition(r.URL.Query(), "q")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rows, err := db.Query(
+		"SELECT "+questionColumns+" FROM product_questions q WHERE q.id = ANY($1) AND q.deleted_at IS NULL AND "+status,
+		pq.Array(strings.Split(idsParam, ",")),
+	)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query questions: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	questions := []Question{}
+	for rows.Next() {
+		q, err := scanQuestion(rows)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to scan question: %v", err), http.StatusInternalServerError)
+			return
+		}
+		questions = append(questions, q)
+	}
+	if err := rows.Err(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to query questions: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(questions)
+}
+
+// getQuestionsByProduct lists a product's approved questions newest first, paginated
+// like listReviewsPage with first/after and last/before over (created_at, id).
+func getQuestionsByProduct(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePageParams(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	conditions := []string{"q.product_id = $1", "q.deleted_at IS NULL", "q.status = '" + StatusApproved + "'"}
+	args := []any{r.PathValue("productId")}
+	if page.after != nil {
+		args = append(args, page.after.createdAt, page.after.id)
+		conditions = append(conditions, fmt.Sprintf("(q.created_at, q.id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+	if page.before != nil {
+		args = append(args, page.before.createdAt, page.before.id)
+		conditions = append(conditions, fmt.Sprintf("(q.created_at, q.id) > ($%d, $%d)", len(args)-1, len(args)))
+	}
+
+	order := "q.created_at DESC, q.id DESC"
+	limit := page.first
+	if page.last > 0 {
+		order = "q.created_at ASC, q.id ASC"
+		limit = page.last
+	}
+
+	query := "SELECT " + questionColumns + " FROM product_questions q WHERE " +
+		strings.Join(conditions, " AND ") + " ORDER BY " + order
+	if limit > 0 {
+		args = append(args, limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query questions: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	questions := []Question{}
+	for rows.Next() {
+		q, err := scanQuestion(rows)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to scan question: %v", err), http.StatusInternalServerError)
+			return
+		}
+		q.Cursor = encodeCursor(q.createdAt, q.ID)
+		questions = append(questions, q)
+	}
+	if err := rows.Err(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to query questions: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if page.last > 0 {
+		slices.Reverse(questions)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(questions)
+}
+
+// deleteQuestion soft-deletes a question together with its answers. They share the
+// deletion time, so restoring the question brings back the same answers.
+func deleteQuestion(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	now := time.Now().UTC()
+
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to begin transaction: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("UPDATE product_questions SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL", now, id)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to delete question: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to check rows affected: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if rowsAffected == 0 {
+		http.Error(w, "question not found", http.StatusNotFound)
+		return
+	}
+
+	if _, err := tx.Exec("UPDATE product_answers SET deleted_at = $1 WHERE question_id = $2 AND deleted_at IS NULL", now, id); err != nil {
+		http.Error(w, fmt.Sprintf("failed to delete answers: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to commit delete: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// restoreQuestion brings back a deleted question and the answers deleted with it.
+// Admin only.
+func restoreQuestion(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to begin transaction: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	var deletedAt time.Time
+	err = tx.QueryRow("SELECT deleted_at FROM product_questions WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE", id).Scan(&deletedAt)
+	if err == sql.ErrNoRows {
+		http.Error(w, "deleted question not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query question: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := tx.Exec("UPDATE product_answers SET deleted_at = NULL WHERE question_id = $1 AND deleted_at = $2", id, deletedAt); err != nil {
+		http.Error(w, fmt.Sprintf("failed to restore answers: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if _, err := tx.Exec("UPDATE product_questions SET deleted_at = NULL WHERE id = $1", id); err != nil {
+		http.Error(w, fmt.Sprintf("failed to restore question: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to commit restore: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeQuestion(w, id, http.StatusOK)
+}
+
+// createAnswer screens a new answer like a review. Only approved questions can be
+// answered.
+func createAnswer(w http.ResponseWriter, r *http.Request) {
+	questionID := r.PathValue("id")
+
+	input, ok := decodePostInput(w, r)
+	if !ok {
+		return
+	}
+
+	result := screener.Screen(input.Body)
+	var id string
+	err := db.QueryRow(`
+		INSERT INTO product_answers (id, question_id, user_id, body, created_at, status, moderation_reason)
+		SELECT $1, id, $3, $4, $5, $6, NULLIF($7, '') FROM product_questions WHERE id = $2 AND status = $8 AND deleted_at IS NULL
+		RETURNING id`,
+		generateID(), questionID, input.UserID, input.Body, time.Now().UTC(), screeningStatus(result.Verdict), result.Reasons(), StatusApproved,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "question not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("failed to insert answer: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeAnswer(w, id, http.StatusCreated)
+}
+
+// getAnswers returns the answers in ids, or every answer to the questions in
+// questionIds. Answers to the same question are listed together in answerOrder.
+// Deleted answers are left out, and so are answers that are not approved unless another
+// status, or "all", is requested.
+func getAnswers(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	status, err := postStatusCondition(q, "a")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var condition, param string
+	switch {
+	case q.Get("ids") != "":
+		condition, param = "a.id = ANY($1)", q.Get("ids")
+	case q.Get("questionIds") != "":
+		condition, param = "a.question_id = ANY($1)", q.Get("questionIds")
+	default:
+		http.Error(w, "ids or questionIds is required", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := db.Query(
+		"SELECT "+answerColumns+" FROM product_answers a WHERE "+condition+" AND a.deleted_at IS NULL AND "+status+" ORDER BY a.question_id, "+answerOrder,
+		pq.Array(strings.Split(param, ",")),
+	)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query answers: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	answers := []Answer{}
+	for rows.Next() {
+		a, err := scanAnswer(rows)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to scan answer: %v", err), http.StatusInternalServerError)
+			return
+		}
+		answers = append(answers, a)
+	}
+	if err := rows.Err(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to query answers: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(answers)
+}
+
+// deleteAnswer soft-deletes an answer. A deleted answer is no longer accepted, so the
+// question's author can accept another one.
+func deleteAnswer(w http.ResponseWriter, r *http.Request) {
+	res, err := db.Exec(
+		"UPDATE product_answers SET deleted_at = $1, accepted = FALSE WHERE id = $2 AND deleted_at IS NULL",
+		time.Now().UTC(), r.PathValue("id"),
+	)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to delete answer: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to check rows affected: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if rowsAffected == 0 {
+		http.Error(w, "answer not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// restoreAnswer brings back a deleted answer, unless its question is deleted too; such
+// answers come back with their question. Admin only.
+func restoreAnswer(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	res, err := db.Exec(`
+		UPDATE product_answers a SET deleted_at = NULL
+		WHERE a.id = $1 AND a.deleted_at IS NOT NULL
+			AND EXISTS (SELECT 1 FROM product_questions q WHERE q.id = a.question_id AND q.deleted_at IS NULL)`,
+		id,
+	)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to restore answer: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to check rows affected: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if rowsAffected == 0 {
+		http.Error(w, "deleted answer not found", http.StatusNotFound)
+		return
+	}
+
+	writeAnswer(w, id, http.StatusOK)
+}
+
+// upvoteAnswer records the user's upvote on an answer. Upvoting twice has no further
+// effect, and authors cannot upvote their own answers.
+func upvoteAnswer(w http.ResponseWriter, r *http.Request) {
+	answerID := r.PathValue("id")
+	userID := r.PathValue("userId")
+
+	var authorID string
+	err := db.QueryRow("SELECT user_id FROM product_answers WHERE id = $1 AND status = $2 AND deleted_at IS NULL", answerID, StatusApproved).Scan(&authorID)
+	if err == sql.ErrNoRows {
+		http.Error(w, "answer not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query answer: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if authorID == userID {
+		http.Error(w, "authors cannot upvote their own answer", http.StatusBadRequest)
+		return
+	}
+
+	// Selecting the answer again covers it being deleted in the meantime; the foreign
+	// key would reject the insert otherwise.
+	_, err = db.Exec(`
+		INSERT INTO answer_upvotes (answer_id, user_id, created_at)
+		SELECT id, $2, $3 FROM product_answers WHERE id = $1 AND status = $4 AND deleted_at IS NULL
+		ON CONFLICT (answer_id, user_id) DO NOTHING`,
+		answerID, userID, time.Now().UTC(), StatusApproved,
+	)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to record upvote: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeAnswer(w, answerID, http.StatusOK)
+}
+
+func retractUpvote(w http.ResponseWriter, r *http.Request) {
+	answerID := r.PathValue("id")
+
+	res, err := db.Exec("DELETE FROM answer_upvotes WHERE answer_id = $1 AND user_id = $2", answerID, r.PathValue("userId"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to retract upvote: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to check rows affected: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if rowsAffected == 0 {
+		http.Error(w, "upvote not found", http.StatusNotFound)
+		return
+	}
+
+	writeAnswer(w, answerID, http.StatusOK)
+}
+
+// acceptAnswer marks one of a question's approved answers as accepted, replacing any
+// answer accepted before.
+func acceptAnswer(w http.ResponseWriter, r *http.Request) {
+	questionID := r.PathValue("id")
+
+	var input struct {
+		AnswerID string `json:"answerId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if input.AnswerID == "" {
+		http.Error(w, "answerId is required", http.StatusBadRequest)
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to begin transaction: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	// Lock the question so concurrent accepts don't both clear and then both set.
+	if !lockQuestion(w, tx, questionID) {
+		return
+	}
+	if _, err := tx.Exec("UPDATE product_answers SET accepted = FALSE WHERE question_id = $1 AND accepted", questionID); err != nil {
+		http.Error(w, fmt.Sprintf("failed to clear accepted answer: %v", err), http.StatusInternalServerError)
+		return
+	}
+	res, err := tx.Exec(
+		"UPDATE product_answers SET accepted = TRUE WHERE id = $1 AND question_id = $2 AND status = $3 AND deleted_at IS NULL",
+		input.AnswerID, questionID, StatusApproved,
+	)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to accept answer: %v", err), http.StatusInternalServerError)
+		return
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to check rows affected: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if rowsAffected == 0 {
+		http.Error(w, "answer not found for this question", http.StatusNotFound)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to commit accepted answer: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeQuestion(w, questionID, http.StatusOK)
+}
+
+// unacceptAnswer clears the question's accepted answer, if it has one.
+func unacceptAnswer(w http.ResponseWriter, r *http.Request) {
+	questionID := r.PathValue("id")
+
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to begin transaction: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	if !lockQuestion(w, tx, questionID) {
+		return
+	}
+	if _, err := tx.Exec("UPDATE product_answers SET accepted = FALSE WHERE question_id = $1 AND accepted", questionID); err != nil {
+		http.Error(w, fmt.Sprintf("failed to clear accepted answer: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to commit accepted answer: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeQuestion(w, questionID, http.StatusOK)
+}
+
+// lockQuestion locks the question row for the rest of tx, writing a 404 when it does
+// not exist.
+func lockQuestion(w http.ResponseWriter, tx *sql.Tx, questionID string) bool {
+	var id string
+	err := tx.QueryRow("SELECT id FROM product_questions WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", questionID).Scan(&id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "question not found", http.StatusNotFound)
+		return false
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("failed to lock question: %v", err), http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+func writeQuestion(w http.ResponseWriter, id string, status int) {
+	q, err := scanQuestion(db.QueryRow("SELECT "+questionColumns+" FROM product_questions q WHERE q.id = $1 AND q.deleted_at IS NULL", id))
+	if err == sql.ErrNoRows {
+		http.Error(w, "question not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query question: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(q)
+}
+
+func writeAnswer(w http.ResponseWriter, id string, status int) {
+	a, err := scanAnswer(db.QueryRow("SELECT "+answerColumns+" FROM product_answers a WHERE a.id = $1 AND a.deleted_at IS NULL", id))
+	if err == sql.ErrNoRows {
+		http.Error(w, "answer not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query answer: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(a)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// ratingPriorWeight is how many reviews' worth of the catalog-wide average rating every
+// product starts with when ranked by rating. It is read from RATING_PRIOR_WEIGHT.
+var ratingPriorWeight = 10.0
+
+// RankedProduct is a product's position in a ranking of products by their reviews.
+type RankedProduct struct {
+	ProductID     string  `json:"productId"`
+	Score         float64 `json:"score"`
+	ReviewCount   int     `json:"reviewCount"`
+	AverageRating float64 `json:"averageRating"`
+}
+
+// productRankings maps the rankBy parameter to the ORDER BY clause of the ranking.
+// Ties are broken by review count, then by product ID so pages are stable.
+var productRankings = map[string]string{
+	"rating":       "score DESC, review_count DESC, product_id",
+	"review_count": "review_count DESC, score DESC, product_id",
+}
+
+func loadRatingPriorWeight() {
+	v := os.Getenv("RATING_PRIOR_WEIGHT")
+	if v == "" {
+		return
+	}
+	w, err := strconv.ParseFloat(v, 64)
+	if err != nil || w < 0 {
+		log.Fatalf("Invalid RATING_PRIOR_WEIGHT %q: expected a non-negative number\n", v)
+	}
+	ratingPriorWeight = w
+}
+
+// getTopProducts ranks the products that have approved reviews, reading the
+// precomputed product_rating_stats.
+//
+// Ranking by rating uses a Bayesian average: each product's ratings are combined with
+// ratingPriorWeight ratings at the catalog-wide mean, so a product needs many good
+// reviews, not one five-star review, to reach the top.
+func getTopProducts(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	rankBy := q.Get("rankBy")
+	if rankBy == "" {
+		rankBy = "rating"
+	}
+	order, ok := productRankings[rankBy]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown rankBy %q", rankBy), http.StatusBadRequest)
+		return
+	}
+
+	limit, err := parsePageSize(q, "limit")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if limit == 0 {
+		limit = 20
+	}
+	offset := 0
+	if v := q.Get("offset"); v != "" {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			http.Error(w, "offset must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+	}
+
+	rows, err := db.Query(`
+		WITH prior AS (
+			SELECT COALESCE(SUM(rating_sum)::float8 / NULLIF(SUM(review_count), 0), 0) AS mean
+			FROM product_rating_stats
+		)
+		SELECT product_id, score, review_count, rating_sum::float8 / review_count
+		FROM (
+			SELECT s.*, ($1 * prior.mean + s.rating_sum) / ($1 + s.review_count) AS score
+			FROM product_rating_stats s, prior
+			WHERE s.review_count > 0
+		) ranked
+		ORDER BY `+order+`
+		LIMIT $2 OFFSET $3`,
+		ratingPriorWeight, limit, offset,
+	)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to rank products: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	ranked := []RankedProduct{}
+	for rows.Next() {
+		var p RankedProduct
+		if err := rows.Scan(&p.ProductID, &p.Score, &p.ReviewCount, &p.AverageRating); err != nil {
+			http.Error(w, fmt.Sprintf("failed to scan ranked product: %v", err), http.StatusInternalServerError)
+			return
+		}
+		ranked = append(ranked, p)
+	}
+	if err := rows.Err(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to rank products: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ranked)
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"time"
+
+	"api/reviews/sentiment"
+)
+
+// ratingStatsColumns are the aggregate columns of product_rating_stats, in the order
+// ratingStatsAggregates computes them.
+const ratingStatsColumns = "review_count, rating_sum, rating_1, rating_2, rating_3, rating_4, rating_5, " +
+	"sentiment_positive, sentiment_neutral, sentiment_negative, sentiment_sum, sentiment_scored"
+
+// ratingStatsAggregates computes ratingStatsColumns over a set of reviews.
+var ratingStatsAggregates = fmt.Sprintf(`COUNT(*), COALESCE(SUM(rating), 0),
+	COUNT(*) FILTER (WHERE rating = 1),
+	COUNT(*) FILTER (WHERE rating = 2),
+	COUNT(*) FILTER (WHERE rating = 3),
+	COUNT(*) FILTER (WHERE rating = 4),
+	COUNT(*) FILTER (WHERE rating = 5),
+	COUNT(*) FILTER (WHERE sentiment_label = '%s'),
+	COUNT(*) FILTER (WHERE sentiment_label = '%s'),
+	COUNT(*) FILTER (WHERE sentiment_label = '%s'),
+	COALESCE(SUM(sentiment_score), 0), COUNT(sentiment_score)`,
+	sentiment.Positive, sentiment.Neutral, sentiment.Negative,
+)
+
+// ratingStatsSource selects the reviews counted in product_rating_stats.
+const ratingStatsSource = "FROM reviews WHERE status = '" + StatusApproved + "' AND deleted_at IS NULL"
+
+// createRatingStatsTable stores the rating and sentiment aggregates of each product's
+// approved reviews, so reads don't have to aggregate the reviews table. A new table is
+// filled from the existing reviews.
+func createRatingStatsTable() error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS product_rating_stats (
+			product_id VARCHAR(255) PRIMARY KEY,
+			review_count INTEGER NOT NULL DEFAULT 0,
+			rating_sum INTEGER NOT NULL DEFAULT 0,
+			rating_1 INTEGER NOT NULL DEFAULT 0,
+			rating_2 INTEGER NOT NULL DEFAULT 0,
+			rating_3 INTEGER NOT NULL DEFAULT 0,
+			rating_4 INTEGER NOT NULL DEFAULT 0,
+			rating_5 INTEGER NOT NULL DEFAULT 0,
+			sentiment_positive INTEGER NOT NULL DEFAULT 0,
+			sentiment_neutral INTEGER NOT NULL DEFAULT 0,
+			sentiment_negative INTEGER NOT NULL DEFAULT 0,
+			sentiment_sum DOUBLE PRECISION NOT NULL DEFAULT 0,
+			sentiment_scored INTEGER NOT NULL DEFAULT 0,
+			updated_at TIMESTAMP NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS product_rating_stats_review_count_idx ON product_rating_stats (review_count DESC, product_id);
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO product_rating_stats (product_id, `+ratingStatsColumns+`, updated_at)
+		SELECT product_id, `+ratingStatsAggregates+`, $1 `+ratingStatsSource+` GROUP BY product_id
+		ON CONFLICT (product_id) DO NOTHING`,
+		time.Now().UTC(),
+	)
+	return err
+}
+
+// syncRatingStats recomputes the stats of a product inside tx, after the transaction has
+// changed which of its reviews are approved or what they say. The stats row is locked
+// before the reviews are aggregated, so of two concurrent writers the second waits and
+// then counts the first one's committed change.
+func syncRatingStats(tx *sql.Tx, productID string) error {
+	now := time.Now().UTC()
+
+	_, err := tx.Exec(
+		"INSERT INTO product_rating_stats (product_id, updated_at) VALUES ($1, $2) ON CONFLICT (product_id) DO NOTHING",
+		productID, now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create rating stats: %v", err)
+	}
+	if _, err := tx.Exec("SELECT 1 FROM product_rating_stats WHERE product_id = $1 FOR UPDATE", productID); err != nil {
+		return fmt.Errorf("failed to lock rating stats: %v", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE product_rating_stats SET (`+ratingStatsColumns+`) = (
+			SELECT `+ratingStatsAggregates+` `+ratingStatsSource+` AND product_id = $1
+		), updated_at = $2
+		WHERE product_id = $1`,
+		productID, now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update rating stats: %v", err)
+	}
+	return nil
+}
+
+// reconcileRatingStats compares product_rating_stats with a fresh aggregate of the
+// reviews and repairs the products that have drifted, for instance after reviews were
+// changed by hand in the database. With -dry-run the drift is only reported.
+func reconcileRatingStats(args []string) error {
+	fs := flag.NewFlagSet("reconcile-stats", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "report drift without repairing it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// Products missing on either side are compared against zero counts.
+	rows, err := db.Query(`
+		SELECT COALESCE(a.product_id, s.product_id), COALESCE(s.review_count, 0), COALESCE(a.review_count, 0)
+		FROM (
+			SELECT product_id, ` + ratingStatsAggregates + ` ` + ratingStatsSource + ` GROUP BY product_id
+		) a (product_id, ` + ratingStatsColumns + `)
+		FULL JOIN product_rating_stats s ON s.product_id = a.product_id
+		WHERE (s.review_count, s.rating_sum, s.rating_1, s.rating_2, s.rating_3, s.rating_4, s.rating_5,
+				s.sentiment_positive, s.sentiment_neutral, s.sentiment_negative, s.sentiment_scored)
+			IS DISTINCT FROM
+			(COALESCE(a.review_count, 0), COALESCE(a.rating_sum, 0), COALESCE(a.rating_1, 0), COALESCE(a.rating_2, 0),
+				COALESCE(a.rating_3, 0), COALESCE(a.rating_4, 0), COALESCE(a.rating_5, 0),
+				COALESCE(a.sentiment_positive, 0), COALESCE(a.sentiment_neutral, 0), COALESCE(a.sentiment_negative, 0),
+				COALESCE(a.sentiment_scored, 0))
+			OR abs(COALESCE(s.sentiment_sum, 0) - COALESCE(a.sentiment_sum, 0)) > 1e-9
+		ORDER BY 1`)
+	if err != nil {
+		return fmt.Errorf("failed to compare rating stats: %v", err)
+	}
+
+	type drift struct {
+		productID        string
+		stored, computed int
+	}
+	var drifted []drift
+	for rows.Next() {
+		var d drift
+		if err := rows.Scan(&d.productID, &d.stored, &d.computed); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan rating stats: %v", err)
+		}
+		drifted = append(drifted, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to compare rating stats: %v", err)
+	}
+
+	for _, d := range drifted {
+		fmt.Printf("Product %s: stored %d reviews, computed %d\n", d.productID, d.stored, d.computed)
+		if *dryRun {
+			continue
+		}
+		if err := repairRatingStats(d.productID); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case len(drifted) == 0:
+		fmt.Println("Rating stats are consistent")
+	case *dryRun:
+		fmt.Printf("Rating stats drifted for %d products\n", len(drifted))
+	default:
+		fmt.Printf("Rating stats repaired for %d products\n", len(drifted))
+	}
+	return nil
+}
+
+func repairRatingStats(productID string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := syncRatingStats(tx, productID); err != nil {
+		return fmt.Errorf("failed to repair product %s: %v", productID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit repair of product %s: %v", productID, err)
+	}
+	return nil
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Reasons a shopper can give for reporting a review.
+const (
+	ReportSpam      = "SPAM"
+	ReportOffensive = "OFFENSIVE"
+	ReportOffTopic  = "OFF_TOPIC"
+	ReportFake      = "FAKE"
+)
+
+// Report statuses. Open reports await a moderator; resolved reports were acted upon and
+// dismissed ones were found to be unfounded.
+const (
+	ReportOpen      = "OPEN"
+	ReportResolved  = "RESOLVED"
+	ReportDismissed = "DISMISSED"
+)
+
+// reportsModerator is recorded as the moderator of reviews hidden automatically because
+// of reports, so dismissing those reports can bring the review back.
+const reportsModerator = "system:reports"
+
+// reportHideThreshold is the number of open reports at which an approved review is
+// hidden automatically. It is read from REPORT_HIDE_THRESHOLD.
+var reportHideThreshold = 3
+
+// ReviewReport is a shopper's complaint about a review.
+type ReviewReport struct {
+	ID             string  `json:"id"`
+	ReviewID       string  `json:"reviewId"`
+	ReporterID     string  `json:"reporterId"`
+	Reason         string  `json:"reason"`
+	Details        string  `json:"details,omitempty"`
+	Status         string  `json:"status"`
+	CreatedAt      string  `json:"createdAt"`
+	ResolvedBy     string  `json:"resolvedBy,omitempty"`
+	ResolvedAt     string  `json:"resolvedAt,omitempty"`
+	ResolutionNote string  `json:"resolutionNote,omitempty"`
+	Review         *Review `json:"review,omitempty"`
+}
+
+// reportColumns lists the columns read by scanReport, in scan order.
+const reportColumns = "id, review_id, reporter_id, reason, details, status, created_at, resolved_by, resolved_at, resolution_note"
+
+func scanReport(row rowScanner) (ReviewReport, error) {
+	var rep ReviewReport
+	var createdAt time.Time
+	var details, resolvedBy, note sql.NullString
+	var resolvedAt sql.NullTime
+	err := row.Scan(&rep.ID, &rep.ReviewID, &rep.ReporterID, &rep.Reason, &details, &rep.Status, &createdAt, &resolvedBy, &resolvedAt, &note)
+	if err != nil {
+		return rep, err
+	}
+	rep.Details = details.String
+	rep.CreatedAt = createdAt.Format(time.RFC3339)
+	rep.ResolvedBy = resolvedBy.String
+	if resolvedAt.Valid {
+		rep.ResolvedAt = resolvedAt.Time.Format(time.RFC3339)
+	}
+	rep.ResolutionNote = note.String
+	return rep, nil
+}
+
+func isValidReportReason(reason string) bool {
+	switch reason {
+	case ReportSpam, ReportOffensive, ReportOffTopic, ReportFake:
+		return true
+	}
+	return false
+}
+
+// createReportsTable stores reports. A shopper can report each review only once.
+func createReportsTable() error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS review_reports (
+			id VARCHAR(255) PRIMARY KEY,
+			review_id VARCHAR(255) NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+			reporter_id VARCHAR(255) NOT NULL,
+			reason VARCHAR(16) NOT NULL,
+			details TEXT,
+			status VARCHAR(16) NOT NULL DEFAULT 'OPEN',
+			created_at TIMESTAMP NOT NULL,
+			resolved_by VARCHAR(255),
+			resolved_at TIMESTAMP,
+			resolution_note TEXT,
+			UNIQUE (review_id, reporter_id)
+		);
+		CREATE INDEX IF NOT EXISTS review_reports_status_created_idx ON review_reports (status, created_at);
+	`)
+	return err
+}
+
+func loadReportHideThreshold() {
+	v := os.Getenv("REPORT_HIDE_THRESHOLD")
+	if v == "" {
+		return
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		log.Fatalf("Invalid REPORT_HIDE_THRESHOLD %q: expected a positive integer\n", v)
+	}
+	reportHideThreshold = n
+}
+
+// createReport records a shopper's report of a published review. Once the review has
+// reportHideThreshold open reports it is hidden until a moderator looks at it.
+func createReport(w http.ResponseWriter, r *http.Request) {
+	reviewID := r.PathValue("id")
+
+	var input ReviewReport
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if input.ReporterID == "" {
+		http.Error(w, "reporterId is required", http.StatusBadRequest)
+		return
+	}
+	if !isValidReportReason(input.Reason) {
+		http.Error(w, fmt.Sprintf("reason must be one of %s, %s, %s or %s", ReportSpam, ReportOffensive, ReportOffTopic, ReportFake), http.StatusBadRequest)
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to begin transaction: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	// Lock the review so concurrent reports agree on when the threshold was crossed.
+	rev, err := scanReview(tx.QueryRow(
+		"SELECT "+reviewColumns+" FROM reviews WHERE id = $1 AND status = $2 AND deleted_at IS NULL FOR UPDATE",
+		reviewID, StatusApproved,
+	))
+	if err == sql.ErrNoRows {
+		http.Error(w, "review not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query review: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if rev.UserID == input.ReporterID {
+		http.Error(w, "authors cannot report their own review", http.StatusBadRequest)
+		return
+	}
+
+	rep, err := scanReport(tx.QueryRow(`
+		INSERT INTO review_reports (id, review_id, reporter_id, reason, details, status, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7)
+		RETURNING `+reportColumns,
+		generateID(), reviewID, input.ReporterID, input.Reason, strings.TrimSpace(input.Details), ReportOpen, time.Now().UTC(),
+	))
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		http.Error(w, "review already reported by this user", http.StatusConflict)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("failed to insert report: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var open int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM review_reports WHERE review_id = $1 AND status = $2", reviewID, ReportOpen).Scan(&open); err != nil {
+		http.Error(w, fmt.Sprintf("failed to count reports: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if open >= reportHideThreshold {
+		rev, err = scanReview(tx.QueryRow(
+			"UPDATE reviews SET status = $1, moderation_reason = $2, moderated_by = $3, moderated_at = $4 WHERE id = $5 RETURNING "+reviewColumns,
+			StatusHidden, fmt.Sprintf("hidden after %d reports", open), reportsModerator, time.Now().UTC(), reviewID,
+		))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to hide review: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if err := syncRatingStats(tx, rev.ProductID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := pruneSummary(tx, rev.ProductID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to commit report: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	rep.Review = &rev
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rep)
+}
+
+// getReports lists reports for moderators, oldest first, each with the reported review
+// whatever its status. Only open reports are listed unless another status, or "all",
+// is requested.
+func getReports(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	conditions := []string{"EXISTS (SELECT 1 FROM reviews WHERE reviews.id = review_id AND reviews.deleted_at IS NULL)"}
+	var args []any
+
+	status := q.Get("status")
+	if status == "" {
+		status = ReportOpen
+	}
+	if status != "all" {
+		if status != ReportOpen && status != ReportResolved && status != ReportDismissed {
+			http.Error(w, fmt.Sprintf("unknown status %q", status), http.StatusBadRequest)
+			return
+		}
+		args = append(args, status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if v := q.Get("reason"); v != "" {
+		if !isValidReportReason(v) {
+			http.Error(w, fmt.Sprintf("unknown reason %q", v), http.StatusBadRequest)
+			return
+		}
+		args = append(args, v)
+		conditions = append(conditions, fmt.Sprintf("reason = $%d", len(args)))
+	}
+	if v := q.Get("reviewIds"); v != "" {
+		args = append(args, pq.Array(strings.Split(v, ",")))
+		conditions = append(conditions, fmt.Sprintf("review_id = ANY($%d)", len(args)))
+	}
+
+	limit := 50
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	args = append(args, limit)
+
+	rows, err := db.Query(
+		"SELECT "+reportColumns+" FROM review_reports WHERE "+strings.Join(conditions, " AND ")+
+			fmt.Sprintf(" ORDER BY created_at, id LIMIT $%d", len(args)),
+		args...,
+	)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query reports: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	reports := []ReviewReport{}
+	var reviewIDs []string
+	for rows.Next() {
+		rep, err := scanReport(rows)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to scan report: %v", err), http.StatusInternalServerError)
+			return
+		}
+		reports = append(reports, rep)
+		reviewIDs = append(reviewIDs, rep.ReviewID)
+	}
+	rows.Close()
+
+	if err := attachReportedReviews(reports, reviewIDs); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
+
+// attachReportedReviews loads the reviews named by reviewIDs, in any status, onto the
+// matching reports.
+func attachReportedReviews(reports []ReviewReport, reviewIDs []string) error {
+	if len(reviewIDs) == 0 {
+		return nil
+	}
+
+	rows, err := db.Query("SELECT "+reviewColumns+" FROM reviews WHERE id = ANY($1)", pq.Array(reviewIDs))
+	if err != nil {
+		return fmt.Errorf("failed to query reported reviews: %v", err)
+	}
+	defer rows.Close()
+
+	reviews := make(map[string]*Review)
+	for rows.Next() {
+		rev, err := scanReview(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan review: %v", err)
+		}
+		reviews[rev.ID] = &rev
+	}
+
+	for i := range reports {
+		reports[i].Review = reviews[reports[i].ReviewID]
+	}
+	return nil
+}
+
+// closeReport returns a handler that closes an open report with the given status.
+// Dismissing the last reports that kept a review hidden republishes it, since the
+// review was only hidden because of them.
+func closeReport(status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("reportId")
+
+		var decision struct {
+			ModeratorID string `json:"moderatorId"`
+			Note        string `json:"note"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&decision); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if decision.ModeratorID == "" {
+			http.Error(w, "moderatorId is required", http.StatusBadRequest)
+			return
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to begin transaction: %v", err), http.StatusInternalServerError)
+			return
+		}
+		defer tx.Rollback()
+
+		now := time.Now().UTC()
+		rep, err := scanReport(tx.QueryRow(`
+			UPDATE review_reports SET status = $1, resolved_by = $2, resolved_at = $3, resolution_note = NULLIF($4, '')
+			WHERE id = $5 AND status = $6
+			RETURNING `+reportColumns,
+			status, decision.ModeratorID, now, strings.TrimSpace(decision.Note), id, ReportOpen,
+		))
+		if err == sql.ErrNoRows {
+			http.Error(w, "open report not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, fmt.Sprintf("failed to close report: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		if status == ReportDismissed {
+			var productID string
+			err := tx.QueryRow(`
+				UPDATE reviews SET status = $1, moderation_reason = NULL, moderated_by = $2, moderated_at = $3
+				WHERE id = $4 AND status = $5 AND moderated_by = $6
+					AND (SELECT COUNT(*) FROM review_reports WHERE review_id = $4 AND status = $7) < $8
+				RETURNING product_id`,
+				StatusApproved, decision.ModeratorID, now, rep.ReviewID, StatusHidden, reportsModerator, ReportOpen, reportHideThreshold,
+			).Scan(&productID)
+			if err != nil && err != sql.ErrNoRows {
+				http.Error(w, fmt.Sprintf("failed to republish review: %v", err), http.StatusInternalServerError)
+				return
+			}
+			if err == nil {
+				if err := syncRatingStats(tx, productID); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			http.Error(w, fmt.Sprintf("failed to commit report: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		reports := []ReviewReport{rep}
+		if err := attachReportedReviews(reports, []string{rep.ReviewID}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(reports[0])
+	}
+}
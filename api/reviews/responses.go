@@ -0,0 +1,203 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ReviewResponse is a merchant's public reply to a review. A review has at most one.
+type ReviewResponse struct {
+	ReviewID    string `json:"reviewId"`
+	ResponderID string `json:"responderId"`
+	Body        string `json:"body"`
+	RespondedAt string `json:"respondedAt"`
+	UpdatedAt   string `json:"updatedAt,omitempty"`
+}
+
+const responseColumns = "review_id, responder_id, body, responded_at, updated_at"
+
+func createResponsesTable() error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS review_responses (
+			review_id VARCHAR(255) PRIMARY KEY REFERENCES reviews(id) ON DELETE CASCADE,
+			responder_id VARCHAR(255) NOT NULL,
+			body TEXT NOT NULL,
+			responded_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP
+		)
+	`)
+	return err
+}
+
+func scanResponse(row rowScanner) (ReviewResponse, error) {
+	var resp ReviewResponse
+	var respondedAt time.Time
+	var updatedAt sql.NullTime
+	if err := row.Scan(&resp.ReviewID, &resp.ResponderID, &resp.Body, &respondedAt, &updatedAt); err != nil {
+		return resp, err
+	}
+	resp.RespondedAt = respondedAt.Format(time.RFC3339)
+	if updatedAt.Valid {
+		resp.UpdatedAt = updatedAt.Time.Format(time.RFC3339)
+	}
+	return resp, nil
+}
+
+// createResponse publishes the response to a review. Only approved reviews that have not
+// been deleted can be responded to.
+func createResponse(w http.ResponseWriter, r *http.Request) {
+	reviewID := r.PathValue("id")
+
+	var input ReviewResponse
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if input.ResponderID == "" || strings.TrimSpace(input.Body) == "" {
+		http.Error(w, "responderId and body are required", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := scanResponse(db.QueryRow(`
+		INSERT INTO review_responses (review_id, responder_id, body, responded_at)
+		SELECT id, $2, $3, $4 FROM reviews WHERE id = $1 AND status = $5 AND deleted_at IS NULL
+		ON CONFLICT (review_id) DO NOTHING
+		RETURNING `+responseColumns,
+		reviewID, input.ResponderID, input.Body, time.Now().UTC(), StatusApproved,
+	))
+	if err == sql.ErrNoRows {
+		// The review does not exist, is not published or already has a response.
+		var status string
+		err := db.QueryRow("SELECT status FROM reviews WHERE id = $1 AND deleted_at IS NULL", reviewID).Scan(&status)
+		if err == sql.ErrNoRows {
+			http.Error(w, "review not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, fmt.Sprintf("failed to query review: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if status != StatusApproved {
+			http.Error(w, "only approved reviews can be responded to", http.StatusConflict)
+			return
+		}
+		http.Error(w, "review already has a response", http.StatusConflict)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("failed to insert response: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func getResponse(w http.ResponseWriter, r *http.Request) {
+	reviewID := r.PathValue("id")
+
+	resp, err := scanResponse(db.QueryRow("SELECT "+responseColumns+" FROM review_responses WHERE review_id = $1", reviewID))
+	if err == sql.ErrNoRows {
+		http.Error(w, "response not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query response: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// getResponses returns the responses to the reviews in reviewIds. Reviews without a
+// response are left out.
+func getResponses(w http.ResponseWriter, r *http.Request) {
+	reviewIdsParam := r.URL.Query().Get("reviewIds")
+	if reviewIdsParam == "" {
+		http.Error(w, "reviewIds is required", http.StatusBadRequest)
+		return
+	}
+	reviewIds := strings.Split(reviewIdsParam, ",")
+
+	rows, err := db.Query("SELECT "+responseColumns+" FROM review_responses WHERE review_id = ANY($1)", pq.Array(reviewIds))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query responses: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	var responseList []ReviewResponse
+	for rows.Next() {
+		resp, err := scanResponse(rows)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to scan response: %v", err), http.StatusInternalServerError)
+			return
+		}
+		responseList = append(responseList, resp)
+	}
+
+	if responseList == nil {
+		responseList = []ReviewResponse{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(responseList)
+}
+
+func updateResponse(w http.ResponseWriter, r *http.Request) {
+	reviewID := r.PathValue("id")
+
+	var input ReviewResponse
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(input.Body) == "" {
+		http.Error(w, "body is required", http.StatusBadRequest)
+		return
+	}
+
+	// The responder is only replaced when the request names one.
+	resp, err := scanResponse(db.QueryRow(
+		"UPDATE review_responses SET body = $1, responder_id = COALESCE(NULLIF($2, ''), responder_id), updated_at = $3 WHERE review_id = $4 RETURNING "+responseColumns,
+		input.Body, input.ResponderID, time.Now().UTC(), reviewID,
+	))
+	if err == sql.ErrNoRows {
+		http.Error(w, "response not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("failed to update response: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func deleteResponse(w http.ResponseWriter, r *http.Request) {
+	reviewID := r.PathValue("id")
+
+	res, err := db.Exec("DELETE FROM review_responses WHERE review_id = $1", reviewID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to delete response: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to check rows affected: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if rowsAffected == 0 {
+		http.Error(w, "response not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
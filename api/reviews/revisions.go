@@ -0,0 +1,116 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Revision is one version of a review's text and rating. Revision 1 is what the author
+// originally wrote; every edit appends the next revision.
+type Revision struct {
+	ReviewID string `json:"reviewId"`
+	Revision int    `json:"revision"`
+	Body     string `json:"body"`
+	Rating   int    `json:"rating"`
+	EditorID string `json:"editorId"`
+	EditedAt string `json:"editedAt"`
+}
+
+func migrateRevisions() error {
+	_, err := db.Exec(`
+		ALTER TABLE reviews ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP;
+		CREATE TABLE IF NOT EXISTS review_revisions (
+			review_id VARCHAR(255) NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+			revision INT NOT NULL,
+			body TEXT NOT NULL,
+			rating INT NOT NULL,
+			editor_id VARCHAR(255) NOT NULL,
+			edited_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (review_id, revision)
+		);
+	`)
+	return err
+}
+
+// appendRevision stores body and rating as the next revision of a review.
+func appendRevision(tx *sql.Tx, reviewID, body string, rating int, editorID string, editedAt any) error {
+	_, err := tx.Exec(`
+		INSERT INTO review_revisions (review_id, revision, body, rating, editor_id, edited_at)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5 FROM review_revisions WHERE review_id = $1`,
+		reviewID, body, rating, editorID, editedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record revision: %v", err)
+	}
+	return nil
+}
+
+// recordRevision appends the edited body and rating to the history of current. Reviews
+// written before revisions were tracked first get their current text recorded as the
+// original revision, so nothing the author wrote is lost. The caller must hold a row
+// lock on the review.
+func recordRevision(tx *sql.Tx, current Review, body string, rating int, editorID string, editedAt time.Time) error {
+	var tracked bool
+	if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM review_revisions WHERE review_id = $1)", current.ID).Scan(&tracked); err != nil {
+		return fmt.Errorf("failed to query revisions: %v", err)
+	}
+	if !tracked {
+		if err := appendRevision(tx, current.ID, current.Body, current.Rating, current.UserID, current.createdAt); err != nil {
+			return err
+		}
+	}
+	return appendRevision(tx, current.ID, body, rating, editorID, editedAt)
+}
+
+// getReviewRevisions lists the revisions of a single review, oldest first.
+func getReviewRevisions(w http.ResponseWriter, r *http.Request) {
+	writeRevisions(w, []string{r.PathValue("id")})
+}
+
+// getRevisions lists the revisions of every review in reviewIds.
+func getRevisions(w http.ResponseWriter, r *http.Request) {
+	reviewIdsParam := r.URL.Query().Get("reviewIds")
+	if reviewIdsParam == "" {
+		http.Error(w, "reviewIds is required", http.StatusBadRequest)
+		return
+	}
+	writeRevisions(w, strings.Split(reviewIdsParam, ","))
+}
+
+func writeRevisions(w http.ResponseWriter, reviewIds []string) {
+	rows, err := db.Query(`
+		SELECT review_id, revision, body, rating, editor_id, edited_at
+		FROM review_revisions
+		WHERE review_id = ANY($1)
+		ORDER BY review_id, revision`, pq.Array(reviewIds))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query revisions: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	var revisionList []Revision
+	for rows.Next() {
+		var rev Revision
+		var t time.Time
+		if err := rows.Scan(&rev.ReviewID, &rev.Revision, &rev.Body, &rev.Rating, &rev.EditorID, &t); err != nil {
+			http.Error(w, fmt.Sprintf("failed to scan revision: %v", err), http.StatusInternalServerError)
+			return
+		}
+		rev.EditedAt = t.Format(time.RFC3339)
+		revisionList = append(revisionList, rev)
+	}
+
+	if revisionList == nil {
+		revisionList = []Revision{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revisionList)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"api/reviews/screening"
+)
+
+// createScreeningFlagsTable stores every finding raised by the screening pipeline when a
+// review was submitted or its text was edited, so moderators can see why it was held or
+// rejected.
+func createScreeningFlagsTable() error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS review_screening_flags (
+			review_id VARCHAR(255) NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+			check_name VARCHAR(64) NOT NULL,
+			verdict VARCHAR(16) NOT NULL,
+			reason TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS review_screening_flags_review_idx ON review_screening_flags (review_id);
+	`)
+	return err
+}
+
+// screeningStatus maps a screening verdict to the status a new review is stored with.
+func screeningStatus(v screening.Verdict) string {
+	switch v {
+	case screening.Reject:
+		return StatusRejected
+	case screening.Hold:
+		return StatusPending
+	}
+	return StatusApproved
+}
+
+// editStatus is the status of an edited review whose new text got verdict v. Text that
+// would hold or reject a new review does the same to an edited one. Clean text sends a
+// rejected review back to the moderation queue, since the author can only ever have one
+// review of the product, but doesn't republish a review a moderator hid.
+func editStatus(current string, v screening.Verdict) string {
+	status := screeningStatus(v)
+	if status == StatusApproved {
+		switch current {
+		case StatusRejected:
+			return StatusPending
+		case StatusHidden:
+			return StatusHidden
+		}
+	}
+	return status
+}
+
+func insertScreeningFlags(tx *sql.Tx, reviewID string, findings []screening.Finding) error {
+	now := time.Now().UTC()
+	for _, f := range findings {
+		_, err := tx.Exec(
+			"INSERT INTO review_screening_flags (review_id, check_name, verdict, reason, created_at) VALUES ($1, $2, $3, $4, $5)",
+			reviewID, f.Check, f.Verdict.String(), f.Reason, now,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record screening flag: %v", err)
+		}
+	}
+	return nil
+}
+
+// getScreeningFlags lists the findings recorded when a review was submitted or edited.
+func getScreeningFlags(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	rows, err := db.Query("SELECT check_name, verdict, reason FROM review_screening_flags WHERE review_id = $1 ORDER BY created_at, check_name", id)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query screening flags: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	var flagList []screening.Finding
+	for rows.Next() {
+		var f screening.Finding
+		var verdict string
+		if err := rows.Scan(&f.Check, &verdict, &f.Reason); err != nil {
+			http.Error(w, fmt.Sprintf("failed to scan screening flag: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if err := f.Verdict.UnmarshalText([]byte(verdict)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		flagList = append(flagList, f)
+	}
+
+	if flagList == nil {
+		flagList = []screening.Finding{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(flagList)
+}
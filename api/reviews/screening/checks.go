@@ -0,0 +1,160 @@
+package screening
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var wordPattern = regexp.MustCompile(`[\p{L}\p{N}']+`)
+
+// BannedWords flags text containing any of a list of words or phrases, ignoring case and
+// punctuation.
+type BannedWords struct {
+	phrases []string
+	verdict Verdict
+}
+
+func NewBannedWords(words []string, verdict Verdict) *BannedWords {
+	b := &BannedWords{verdict: verdict}
+	for _, w := range words {
+		if phrase := normalizeWords(w); phrase != "" {
+			b.phrases = append(b.phrases, phrase)
+		}
+	}
+	return b
+}
+
+func (b *BannedWords) Name() string { return "banned_words" }
+
+func (b *BannedWords) Screen(text string) []Finding {
+	// Pad with spaces so phrases only match on whole-word boundaries.
+	normalized := " " + normalizeWords(text) + " "
+	var findings []Finding
+	for _, phrase := range b.phrases {
+		if strings.Contains(normalized, " "+phrase+" ") {
+			findings = append(findings, Finding{
+				Check:   b.Name(),
+				Verdict: b.verdict,
+				Reason:  fmt.Sprintf("contains banned term %q", phrase),
+			})
+		}
+	}
+	return findings
+}
+
+// normalizeWords lower-cases text and reduces it to its words separated by single spaces.
+func normalizeWords(text string) string {
+	return strings.Join(wordPattern.FindAllString(strings.ToLower(text), -1), " ")
+}
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+|\b[a-z0-9-]+(?:\.[a-z0-9-]+)*\.(?:com|net|org|io|co|info|biz|xyz|shop|ru|cn)\b`)
+
+// LinkCheck flags URLs and bare domain names.
+type LinkCheck struct {
+	Verdict Verdict
+}
+
+func (LinkCheck) Name() string { return "links" }
+
+func (c LinkCheck) Screen(text string) []Finding {
+	for _, loc := range linkPattern.FindAllStringIndex(text, -1) {
+		// The domain part of an email address is reported by PersonalDataCheck instead.
+		if loc[0] > 0 && text[loc[0]-1] == '@' {
+			continue
+		}
+		return []Finding{{Check: c.Name(), Verdict: c.Verdict, Reason: "contains a link"}}
+	}
+	return nil
+}
+
+// CapsCheck flags text written mostly in capital letters.
+type CapsCheck struct {
+	MinLetters int
+	MaxRatio   float64
+	Verdict    Verdict
+}
+
+func (CapsCheck) Name() string { return "excessive_caps" }
+
+func (c CapsCheck) Screen(text string) []Finding {
+	letters, upper := 0, 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	if letters == 0 || letters < c.MinLetters {
+		return nil
+	}
+	if ratio := float64(upper) / float64(letters); ratio > c.MaxRatio {
+		return []Finding{{
+			Check:   c.Name(),
+			Verdict: c.Verdict,
+			Reason:  fmt.Sprintf("%.0f%% of letters are capitals", ratio*100),
+		}}
+	}
+	return nil
+}
+
+// RepeatedCharsCheck flags long runs of the same character, such as "!!!!!!!" or "soooooo".
+type RepeatedCharsCheck struct {
+	MaxRun  int
+	Verdict Verdict
+}
+
+func (RepeatedCharsCheck) Name() string { return "repeated_characters" }
+
+func (c RepeatedCharsCheck) Screen(text string) []Finding {
+	if c.MaxRun < 1 {
+		return nil
+	}
+	var prev rune
+	run := 0
+	for _, r := range text {
+		if r == prev && !unicode.IsSpace(r) {
+			run++
+		} else {
+			prev, run = r, 1
+		}
+		if run > c.MaxRun {
+			return []Finding{{
+				Check:   c.Name(),
+				Verdict: c.Verdict,
+				Reason:  fmt.Sprintf("repeats %q more than %d times", r, c.MaxRun),
+			}}
+		}
+	}
+	return nil
+}
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+// phonePattern matches phone-shaped numbers only: international numbers starting with
+// +, North American 3-3-4 groupings and national numbers with a leading 0. Dates, times
+// and order numbers such as 2024-01-15 or 12.03.2024 don't fit these groupings.
+var phonePattern = regexp.MustCompile(`\+\d[\d\s().-]{7,18}\d` +
+	`|\(?\b\d{3}\)?[\s.-]?\d{3}[\s.-]\d{4}\b` +
+	`|\b0\d{2,4}[\s-]?\d{3,4}[\s-]?\d{3,4}\b`)
+
+// PersonalDataCheck flags email addresses and phone numbers.
+type PersonalDataCheck struct {
+	Verdict Verdict
+}
+
+func (PersonalDataCheck) Name() string { return "personal_data" }
+
+func (c PersonalDataCheck) Screen(text string) []Finding {
+	var findings []Finding
+	if emailPattern.MatchString(text) {
+		findings = append(findings, Finding{Check: c.Name(), Verdict: c.Verdict, Reason: "contains an email address"})
+	}
+	if phonePattern.MatchString(text) {
+		findings = append(findings, Finding{Check: c.Name(), Verdict: c.Verdict, Reason: "contains a phone number"})
+	}
+	return findings
+}
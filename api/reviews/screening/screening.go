@@ -0,0 +1,172 @@
+// Package screening runs review text through a configurable set of content checks and
+// decides whether the review can be published straight away, has to wait for a
+// moderator, or is rejected outright.
+package screening
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Verdict is the outcome of screening. Verdicts are ordered by severity, so the verdict
+// of a pipeline is the most severe verdict of any of its findings.
+type Verdict int
+
+const (
+	Approve Verdict = iota
+	Hold
+	Reject
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case Hold:
+		return "hold"
+	case Reject:
+		return "reject"
+	}
+	return "approve"
+}
+
+func (v Verdict) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+func (v *Verdict) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "approve":
+		*v = Approve
+	case "hold":
+		*v = Hold
+	case "reject":
+		*v = Reject
+	default:
+		return fmt.Errorf("unknown verdict %q", text)
+	}
+	return nil
+}
+
+// Finding is a single reason a check flagged a review.
+type Finding struct {
+	Check   string  `json:"check"`
+	Verdict Verdict `json:"verdict"`
+	Reason  string  `json:"reason"`
+}
+
+// Check inspects review text and reports anything it objects to.
+type Check interface {
+	Name() string
+	Screen(text string) []Finding
+}
+
+// Result is the combined outcome of all checks in a pipeline.
+type Result struct {
+	Verdict  Verdict
+	Findings []Finding
+}
+
+// Reasons joins the reasons of all findings into a single line for moderators.
+func (r Result) Reasons() string {
+	reasons := make([]string, len(r.Findings))
+	for i, f := range r.Findings {
+		reasons[i] = f.Reason
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// Pipeline runs a sequence of checks over review text.
+type Pipeline struct {
+	checks []Check
+}
+
+func NewPipeline(checks ...Check) *Pipeline {
+	return &Pipeline{checks: checks}
+}
+
+// Add appends further checks to the pipeline.
+func (p *Pipeline) Add(checks ...Check) {
+	p.checks = append(p.checks, checks...)
+}
+
+// Screen runs every check and returns the most severe verdict together with all findings.
+// Text that no check objects to is approved.
+func (p *Pipeline) Screen(text string) Result {
+	var result Result
+	for _, c := range p.checks {
+		for _, f := range c.Screen(text) {
+			if f.Verdict > result.Verdict {
+				result.Verdict = f.Verdict
+			}
+			result.Findings = append(result.Findings, f)
+		}
+	}
+	return result
+}
+
+// Config selects and tunes the built-in checks. The verdict fields decide what happens
+// to a review that a check flags.
+type Config struct {
+	BannedWords        []string `json:"bannedWords"`
+	BannedWordsVerdict Verdict  `json:"bannedWordsVerdict"`
+
+	LinksVerdict Verdict `json:"linksVerdict"`
+
+	// Text with at least CapsMinLetters letters is flagged when more than CapsMaxRatio
+	// of them are upper case.
+	CapsMinLetters int     `json:"capsMinLetters"`
+	CapsMaxRatio   float64 `json:"capsMaxRatio"`
+	CapsVerdict    Verdict `json:"capsVerdict"`
+
+	// Runs of the same character longer than MaxRepeatedChars are flagged.
+	MaxRepeatedChars     int     `json:"maxRepeatedChars"`
+	RepeatedCharsVerdict Verdict `json:"repeatedCharsVerdict"`
+	PersonalDataVerdict  Verdict `json:"personalDataVerdict"`
+}
+
+// DefaultConfig rejects banned words and holds everything else that looks suspicious.
+func DefaultConfig() Config {
+	return Config{
+		BannedWords:          []string{"viagra", "casino", "crypto giveaway", "free money"},
+		BannedWordsVerdict:   Reject,
+		LinksVerdict:         Hold,
+		CapsMinLetters:       20,
+		CapsMaxRatio:         0.7,
+		CapsVerdict:          Hold,
+		MaxRepeatedChars:     5,
+		RepeatedCharsVerdict: Hold,
+		PersonalDataVerdict:  Hold,
+	}
+}
+
+// LoadConfig reads a JSON config file on top of DefaultConfig. An empty path returns the
+// defaults.
+func LoadConfig(path string) (Config, error) {
+	cfg := DefaultConfig()
+	if path == "" {
+		return cfg, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to open screening config: %v", err)
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("failed to decode screening config: %v", err)
+	}
+	return cfg, nil
+}
+
+// NewPipelineFromConfig builds a pipeline with all built-in checks.
+func NewPipelineFromConfig(cfg Config) *Pipeline {
+	return NewPipeline(
+		NewBannedWords(cfg.BannedWords, cfg.BannedWordsVerdict),
+		LinkCheck{Verdict: cfg.LinksVerdict},
+		CapsCheck{MinLetters: cfg.CapsMinLetters, MaxRatio: cfg.CapsMaxRatio, Verdict: cfg.CapsVerdict},
+		RepeatedCharsCheck{MaxRun: cfg.MaxRepeatedChars, Verdict: cfg.RepeatedCharsVerdict},
+		PersonalDataCheck{Verdict: cfg.PersonalDataVerdict},
+	)
+}
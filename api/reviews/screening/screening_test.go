@@ -0,0 +1,63 @@
+package screening
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDefaultPipeline(t *testing.T) {
+	pipeline := NewPipelineFromConfig(DefaultConfig())
+
+	tests := []struct {
+		name    string
+		text    string
+		verdict Verdict
+		checks  []string
+	}{
+		{"clean", "Solid keyboard, the keys feel great and it was easy to set up.", Approve, nil},
+		{"banned word", "Win big at the CASINO tonight", Reject, []string{"banned_words"}},
+		{"banned phrase across punctuation", "Join the crypto-giveaway now", Reject, []string{"banned_words"}},
+		{"banned word inside another word", "Occasionally the casinos of Vegas came to mind", Approve, nil},
+		{"link", "Cheaper at https://example.com/deal", Hold, []string{"links"}},
+		{"bare domain", "Cheaper on deals.shop right now", Hold, []string{"links"}},
+		{"email address", "Write to me at jane@example.com for details", Hold, []string{"personal_data"}},
+		{"phone number", "Call me on +1 (555) 123-4567", Hold, []string{"personal_data"}},
+		{"grouped phone number", "Text me at 555-123-4567", Hold, []string{"personal_data"}},
+		{"national phone number", "Ring 020 7946 0958 after six", Hold, []string{"personal_data"}},
+		{"date and time", "Delivered on 2024-01-15 10:30 as promised", Approve, nil},
+		{"dotted date", "Bought it on 12.03.2024 and still happy", Approve, nil},
+		{"order number", "Order 20240115-1030 arrived in two days", Approve, nil},
+		{"capitals", "THIS IS THE BEST KEYBOARD I HAVE EVER OWNED", Hold, []string{"excessive_caps"}},
+		{"short capitals", "WOW, LOVE IT", Approve, nil},
+		{"repeated characters", "Sooooooo good!!!!!!", Hold, []string{"repeated_characters"}},
+		{"most severe verdict wins", "FREE MONEY AT WWW.EXAMPLE.COM RIGHT NOW", Reject, []string{"banned_words", "links", "excessive_caps"}},
+	}
+	for _, tt := range tests {
+		result := pipeline.Screen(tt.text)
+		if result.Verdict != tt.verdict {
+			t.Errorf("%s: verdict = %s, want %s (%s)", tt.name, result.Verdict, tt.verdict, result.Reasons())
+		}
+		var checks []string
+		for _, f := range result.Findings {
+			checks = append(checks, f.Check)
+		}
+		if !slices.Equal(checks, tt.checks) {
+			t.Errorf("%s: checks = %q, want %q", tt.name, checks, tt.checks)
+		}
+	}
+}
+
+func TestVerdictText(t *testing.T) {
+	for _, v := range []Verdict{Approve, Hold, Reject} {
+		text, _ := v.MarshalText()
+		var got Verdict
+		if err := got.UnmarshalText(text); err != nil || got != v {
+			t.Errorf("UnmarshalText(%q) = %s, %v, want %s", text, got, err, v)
+		}
+	}
+
+	var v Verdict
+	if err := v.UnmarshalText([]byte("ban")); err == nil {
+		t.Error("UnmarshalText(\"ban\") succeeded, want an error")
+	}
+}
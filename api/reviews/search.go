@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ts_headline marks matched terms with these private-use characters rather than HTML, so
+// the snippet can be escaped before the <mark> tags are put in. They are removed from
+// the body first so review text can't add marks of its own.
+const (
+	snippetStart = "\ue000"
+	snippetStop  = "\ue001"
+)
+
+// searchHeadlineOptions configures the ts_headline snippets returned with search
+// results.
+const searchHeadlineOptions = "StartSel=" + snippetStart + ", StopSel=" + snippetStop + ", MaxWords=35, MinWords=15, MaxFragments=2"
+
+var snippetMarks = strings.NewReplacer(snippetStart, "<mark>", snippetStop, "</mark>")
+
+// highlightSnippet HTML-escapes a ts_headline snippet and wraps the matched terms in
+// <mark> tags, so the snippet is safe to render as HTML.
+func highlightSnippet(headline string) string {
+	return snippetMarks.Replace(html.EscapeString(headline))
+}
+
+// SearchHit is a review matching a full-text search, with its relevance and a snippet
+// of the body highlighting the matched terms.
+type SearchHit struct {
+	Review
+	Rank    float32 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+// searchCursor identifies a hit's position in the (rank, id) ordering of a search.
+type searchCursor struct {
+	rank float32
+	id   string
+}
+
+func encodeSearchCursor(rank float32, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatFloat(float64(rank), 'g', -1, 32) + "," + id))
+}
+
+func decodeSearchCursor(s string) (*searchCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	rank, id, ok := strings.Cut(string(raw), ",")
+	if !ok || id == "" {
+		return nil, errors.New("invalid cursor")
+	}
+	f, err := strconv.ParseFloat(rank, 32)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	return &searchCursor{rank: float32(f), id: id}, nil
+}
+
+// migrateSearch keeps an English tsvector of every review body, indexed for full-text
+// search.
+func migrateSearch() error {
+	_, err := db.Exec(`
+		ALTER TABLE reviews ADD COLUMN IF NOT EXISTS body_tsv tsvector
+			GENERATED ALWAYS AS (to_tsvector('english', body)) STORED;
+		CREATE INDEX IF NOT EXISTS reviews_body_tsv_idx ON reviews USING GIN (body_tsv);
+	`)
+	return err
+}
+
+// searchReviews finds approved reviews whose body matches q, most relevant first. The
+// query uses web search syntax: "battery or refund", quoted phrases and -exclusions.
+func searchReviews(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	text := strings.TrimSpace(q.Get("q"))
+	if text == "" {
+		http.Error(w, "q is required", http.StatusBadRequest)
+		return
+	}
+
+	first, err := parsePageSize(q, "first")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if first == 0 {
+		first = maxPageSize
+	}
+
+	conditions := []string{"body_tsv @@ query", "status = '" + StatusApproved + "'", "deleted_at IS NULL"}
+	args := []any{text}
+	if v := q.Get("productId"); v != "" {
+		args = append(args, v)
+		conditions = append(conditions, fmt.Sprintf("product_id = $%d", len(args)))
+	}
+	if v := q.Get("minRating"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 5 {
+			http.Error(w, "minRating must be between 1 and 5", http.StatusBadRequest)
+			return
+		}
+		args = append(args, n)
+		conditions = append(conditions, fmt.Sprintf("rating >= $%d", len(args)))
+	}
+	if v := q.Get("after"); v != "" {
+		after, err := decodeSearchCursor(v)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		args = append(args, after.rank, after.id)
+		conditions = append(conditions, fmt.Sprintf("(ts_rank(body_tsv, query), id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+	args = append(args, first)
+
+	// Snippets are only built for the rows on the page, since ts_headline reparses the
+	// whole body.
+	query := fmt.Sprintf(`
+		SELECT %[1]s, rank, ts_headline('english', translate(body, '%[5]s', ''), query, '%[2]s') FROM (
+			SELECT %[1]s, ts_rank(body_tsv, query) AS rank, query
+			FROM reviews, websearch_to_tsquery('english', $1) AS query
+			WHERE %[3]s
+			ORDER BY rank DESC, id DESC
+			LIMIT $%[4]d
+		) hits
+		ORDER BY rank DESC, id DESC`,
+		reviewColumns, searchHeadlineOptions, strings.Join(conditions, " AND "), len(args), snippetStart+snippetStop,
+	)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to search reviews: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	hits := []SearchHit{}
+	for rows.Next() {
+		var hit SearchHit
+		hit.Review, err = scanReview(withExtraColumns(rows, &hit.Rank, &hit.Snippet))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to scan review: %v", err), http.StatusInternalServerError)
+			return
+		}
+		hit.Snippet = highlightSnippet(hit.Snippet)
+		hit.Cursor = encodeSearchCursor(hit.Rank, hit.ID)
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to search reviews: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hits)
+}
+
+// extraColumns scans columns selected after reviewColumns into extra.
+type extraColumns struct {
+	row   rowScanner
+	extra []any
+}
+
+func withExtraColumns(row rowScanner, extra ...any) rowScanner {
+	return extraColumns{row: row, extra: extra}
+}
+
+func (s extraColumns) Scan(dest ...any) error {
+	return s.row.Scan(append(dest, s.extra...)...)
+}
@@ -0,0 +1,20 @@
+package main
+
+import "testing"
+
+func TestHighlightSnippet(t *testing.T) {
+	tests := []struct {
+		headline string
+		want     string
+	}{
+		{"The " + snippetStart + "battery" + snippetStop + " died", "The <mark>battery</mark> died"},
+		{"<script>alert(1)</script> " + snippetStart + "refund" + snippetStop, "&lt;script&gt;alert(1)&lt;/script&gt; <mark>refund</mark>"},
+		{`Say "hi" & <mark>bye</mark>`, "Say &#34;hi&#34; &amp; &lt;mark&gt;bye&lt;/mark&gt;"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := highlightSnippet(tt.headline); got != tt.want {
+			t.Errorf("highlightSnippet(%q) = %q, want %q", tt.headline, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+
+	"api/reviews/sentiment"
+)
+
+// ReviewSentiment is the sentiment of a review's body. RatingMismatch flags reviews
+// whose text clearly disagrees with their star rating, such as five stars on a
+// complaint.
+type ReviewSentiment struct {
+	sentiment.Result
+	RatingMismatch bool `json:"ratingMismatch"`
+}
+
+// SentimentBreakdown counts a product's approved reviews by sentiment label.
+type SentimentBreakdown struct {
+	Positive     int      `json:"positive"`
+	Neutral      int      `json:"neutral"`
+	Negative     int      `json:"negative"`
+	AverageScore *float64 `json:"averageScore"`
+}
+
+func migrateSentiment() error {
+	_, err := db.Exec(`
+		ALTER TABLE reviews ADD COLUMN IF NOT EXISTS sentiment_score DOUBLE PRECISION;
+		ALTER TABLE reviews ADD COLUMN IF NOT EXISTS sentiment_label VARCHAR(16);
+		ALTER TABLE reviews ADD COLUMN IF NOT EXISTS rating_mismatch BOOLEAN;
+		CREATE INDEX IF NOT EXISTS reviews_rating_mismatch_idx ON reviews (created_at DESC) WHERE rating_mismatch;
+	`)
+	return err
+}
+
+func analyzeSentiment(body string, rating int) *ReviewSentiment {
+	result := sentiment.Analyze(body)
+	return &ReviewSentiment{Result: result, RatingMismatch: sentiment.Disagrees(rating, result)}
+}
+
+// scanSentiment converts the nullable sentiment columns of a review into a
+// ReviewSentiment, or nil for reviews that have not been scored yet.
+func scanSentiment(score sql.NullFloat64, label sql.NullString, mismatch sql.NullBool) *ReviewSentiment {
+	if !label.Valid {
+		return nil
+	}
+	return &ReviewSentiment{
+		Result:         sentiment.Result{Score: score.Float64, Label: sentiment.Label(label.String)},
+		RatingMismatch: mismatch.Bool,
+	}
+}
+
+// backfillSentiment scores reviews written before sentiment was tracked. With -all,
+// every review is rescored, which picks up changes to the lexicon.
+func backfillSentiment(args []string) error {
+	fs := flag.NewFlagSet("backfill-sentiment", flag.ContinueOnError)
+	all := fs.Bool("all", false, "rescore reviews that already have a sentiment")
+	batchSize := fs.Int("batch", 500, "number of reviews to score per transaction")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	pending := "sentiment_label IS NULL"
+	if *all {
+		pending = ""
+	}
+
+	total, err := backfillReviews(pending, *batchSize, func(tx *sql.Tx, id, body string, rating int) error {
+		scored := analyzeSentiment(body, rating)
+		var productID string
+		err := tx.QueryRow(
+			"UPDATE reviews SET sentiment_score = $1, sentiment_label = $2, rating_mismatch = $3 WHERE id = $4 RETURNING product_id",
+			scored.Score, scored.Label, scored.RatingMismatch, id,
+		).Scan(&productID)
+		if err != nil {
+			return err
+		}
+		return syncRatingStats(tx, productID)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Sentiment backfill complete: %d reviews scored\n", total)
+	return nil
+}
@@ -0,0 +1,181 @@
+// Package sentiment scores the sentiment of review text against an embedded word list,
+// without calling any external service.
+package sentiment
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Label classifies a sentiment score.
+type Label string
+
+const (
+	Positive Label = "POSITIVE"
+	Neutral  Label = "NEUTRAL"
+	Negative Label = "NEGATIVE"
+)
+
+// neutralBand is how far a score may stray from zero and still be labelled neutral.
+const neutralBand = 0.05
+
+// mismatchThreshold is how strongly the text must lean the other way before it is
+// considered to contradict the star rating.
+const mismatchThreshold = 0.3
+
+// normalization controls how quickly the summed word scores approach ±1.
+const normalization = 15
+
+// negationWindow is how many words after a negator have their score flipped.
+const negationWindow = 3
+
+//go:embed lexicon.txt
+var lexiconData string
+
+var lexicon = mustParseLexicon(lexiconData)
+
+var negators = map[string]bool{
+	"not": true, "no": true, "never": true, "without": true, "hardly": true,
+	"nothing": true, "neither": true, "nor": true, "cannot": true,
+}
+
+var intensifiers = map[string]float64{
+	"very": 1.5, "really": 1.5, "extremely": 1.8, "super": 1.5, "so": 1.3,
+	"incredibly": 1.8, "absolutely": 1.6, "totally": 1.5, "highly": 1.5,
+	"slightly": 0.5, "somewhat": 0.6, "kinda": 0.6, "fairly": 0.8, "pretty": 1.2,
+}
+
+// Result is the sentiment of a piece of text. Score ranges from -1 (very negative) to
+// 1 (very positive).
+type Result struct {
+	Score float64 `json:"score"`
+	Label Label   `json:"label"`
+}
+
+// Analyze scores text by summing the lexicon valence of its words. A negator flips the
+// words that follow it, an intensifier scales the next word, and after "but" the rest
+// of the sentence outweighs what came before it.
+func Analyze(text string) Result {
+	var sum float64
+	for _, sentence := range Sentences(text) {
+		sum += scoreSentence(sentence)
+	}
+
+	score := sum / math.Sqrt(sum*sum+normalization)
+	return Result{Score: score, Label: LabelFor(score)}
+}
+
+// IsOpinionWord reports whether word carries sentiment of its own, such as "great" or
+// "broken".
+func IsOpinionWord(word string) bool {
+	_, ok := lexicon[word]
+	return ok
+}
+
+// Disagrees reports whether the text leans clearly against a star rating: a positive
+// rating with negative text, or a negative rating with positive text.
+func Disagrees(rating int, r Result) bool {
+	switch {
+	case rating >= 4:
+		return r.Score <= -mismatchThreshold
+	case rating <= 2:
+		return r.Score >= mismatchThreshold
+	}
+	return false
+}
+
+func scoreSentence(sentence string) float64 {
+	var before, after float64
+	afterBut := false
+
+	// Negation and intensifiers don't reach past a comma: in "not cheap, but great"
+	// only "cheap" is negated.
+	for _, clause := range strings.Split(sentence, ",") {
+		negated := 0
+		boost := 1.0
+
+		for _, w := range Words(clause) {
+			switch {
+			case w == "but":
+				afterBut = true
+				negated = 0
+				continue
+			case negators[w] || strings.HasSuffix(w, "n't"):
+				negated = negationWindow
+				continue
+			}
+			if f, ok := intensifiers[w]; ok {
+				boost *= f
+				continue
+			}
+
+			v := lexicon[w] * boost
+			boost = 1
+			if negated > 0 {
+				// "not good" is weaker than "bad", so negation only partly reverses a word.
+				v *= -0.75
+				negated--
+			}
+			if afterBut {
+				after += v
+			} else {
+				before += v
+			}
+		}
+	}
+
+	if afterBut {
+		return before*0.5 + after*1.5
+	}
+	return before
+}
+
+// LabelFor classifies a score, such as an average over several results.
+func LabelFor(score float64) Label {
+	switch {
+	case score >= neutralBand:
+		return Positive
+	case score <= -neutralBand:
+		return Negative
+	}
+	return Neutral
+}
+
+// Sentences splits text at sentence-ending punctuation and line breaks.
+func Sentences(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return r == '.' || r == '!' || r == '?' || r == ';' || r == '\n'
+	})
+}
+
+// Words lowercases text and splits it into words, keeping apostrophes so that
+// contractions such as "isn't" survive.
+func Words(text string) []string {
+	text = strings.ToLower(strings.ReplaceAll(text, "’", "'"))
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+}
+
+func mustParseLexicon(data string) map[string]float64 {
+	words := make(map[string]float64)
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		word, value, ok := strings.Cut(text, " ")
+		score, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if !ok || err != nil {
+			panic(fmt.Sprintf("sentiment: invalid lexicon entry on line %d: %q", line, text))
+		}
+		words[word] = score
+	}
+	return words
+}
@@ -0,0 +1,113 @@
+package sentiment
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		text  string
+		label Label
+	}{
+		{"Great keyboard, I love it", Positive},
+		{"The keys broke after a week", Negative},
+		{"It is a keyboard", Neutral},
+		{"", Neutral},
+		{"not good", Negative},
+		{"not bad", Positive},
+		{"I don't like it", Negative},
+		// Negation stops at the comma, so only "cheap" is negated.
+		{"Not cheap, but great", Positive},
+		// What follows "but" outweighs what came before it.
+		{"Great at first but it broke", Negative},
+	}
+	for _, tt := range tests {
+		r := Analyze(tt.text)
+		if r.Label != tt.label {
+			t.Errorf("Analyze(%q) = %.3f %s, want %s", tt.text, r.Score, r.Label, tt.label)
+		}
+		if r.Score < -1 || r.Score > 1 {
+			t.Errorf("Analyze(%q).Score = %f, want within [-1, 1]", tt.text, r.Score)
+		}
+	}
+}
+
+func TestAnalyzeModifiers(t *testing.T) {
+	tests := []struct {
+		name            string
+		weaker, greater string
+	}{
+		{"negation only partly reverses", "bad", "not good"},
+		{"intensifier strengthens", "good", "very good"},
+		{"downtoner weakens", "slightly good", "good"},
+	}
+	for _, tt := range tests {
+		weaker, greater := Analyze(tt.weaker).Score, Analyze(tt.greater).Score
+		if weaker >= greater {
+			t.Errorf("%s: Analyze(%q) = %.3f, want below Analyze(%q) = %.3f", tt.name, tt.weaker, weaker, tt.greater, greater)
+		}
+	}
+
+	// "good" and "bad" have opposite valences, so negating them gives opposite scores.
+	if notGood, notBad := Analyze("not good").Score, Analyze("not bad").Score; math.Abs(notGood+notBad) > 1e-9 {
+		t.Errorf("Analyze(\"not good\") = %.3f, want the opposite of Analyze(\"not bad\") = %.3f", notGood, notBad)
+	}
+}
+
+func TestDisagrees(t *testing.T) {
+	tests := []struct {
+		rating int
+		score  float64
+		want   bool
+	}{
+		{5, -mismatchThreshold, true},
+		{5, -mismatchThreshold + 0.01, false},
+		{4, -0.9, true},
+		{4, 0.9, false},
+		{3, -0.9, false},
+		{3, 0.9, false},
+		{2, mismatchThreshold, true},
+		{2, mismatchThreshold - 0.01, false},
+		{1, 0.9, true},
+		{1, -0.9, false},
+	}
+	for _, tt := range tests {
+		if got := Disagrees(tt.rating, Result{Score: tt.score}); got != tt.want {
+			t.Errorf("Disagrees(%d, %.2f) = %v, want %v", tt.rating, tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestLabelFor(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  Label
+	}{
+		{1, Positive},
+		{neutralBand, Positive},
+		{neutralBand - 0.01, Neutral},
+		{0, Neutral},
+		{-neutralBand + 0.01, Neutral},
+		{-neutralBand, Negative},
+		{-1, Negative},
+	}
+	for _, tt := range tests {
+		if got := LabelFor(tt.score); got != tt.want {
+			t.Errorf("LabelFor(%.2f) = %s, want %s", tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestWords(t *testing.T) {
+	got := Words("It ISN’T great: 10/10, really!")
+	want := []string{"it", "isn't", "great", "10", "10", "really"}
+	if len(got) != len(want) {
+		t.Fatalf("Words() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Words() = %q, want %q", got, want)
+		}
+	}
+}
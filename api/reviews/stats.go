@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+type RatingBucket struct {
+	Stars int `json:"stars"`
+	Count int `json:"count"`
+}
+
+type ProductRatingStats struct {
+	ProductID     string         `json:"productId"`
+	AverageRating *float64       `json:"averageRating"`
+	ReviewCount   int            `json:"reviewCount"`
+	Histogram     []RatingBucket `json:"histogram"`
+
+	Sentiment SentimentBreakdown `json:"sentiment"`
+}
+
+// getReviewStats returns rating and sentiment aggregates over the approved reviews of
+// each requested product, read from product_rating_stats. Products without reviews are
+// reported with zero counts and no average.
+func getReviewStats(w http.ResponseWriter, r *http.Request) {
+	productIdsParam := r.URL.Query().Get("productIds")
+	if productIdsParam == "" {
+		http.Error(w, "productIds is required", http.StatusBadRequest)
+		return
+	}
+	productIds := strings.Split(productIdsParam, ",")
+
+	rows, err := db.Query(
+		"SELECT product_id, "+ratingStatsColumns+" FROM product_rating_stats WHERE product_id = ANY($1) AND review_count > 0",
+		pq.Array(productIds),
+	)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query review stats: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	statsMap := make(map[string]ProductRatingStats)
+	for rows.Next() {
+		var s ProductRatingStats
+		var ratingSum, scored int
+		var sentimentSum float64
+		var counts [5]int
+		err := rows.Scan(
+			&s.ProductID, &s.ReviewCount, &ratingSum, &counts[0], &counts[1], &counts[2], &counts[3], &counts[4],
+			&s.Sentiment.Positive, &s.Sentiment.Neutral, &s.Sentiment.Negative, &sentimentSum, &scored,
+		)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to scan review stats: %v", err), http.StatusInternalServerError)
+			return
+		}
+		avg := float64(ratingSum) / float64(s.ReviewCount)
+		s.AverageRating = &avg
+		s.Histogram = histogram(counts)
+		if scored > 0 {
+			avgSentiment := sentimentSum / float64(scored)
+			s.Sentiment.AverageScore = &avgSentiment
+		}
+		statsMap[s.ProductID] = s
+	}
+
+	statsList := make([]ProductRatingStats, 0, len(productIds))
+	for _, id := range productIds {
+		s, found := statsMap[id]
+		if !found {
+			s = ProductRatingStats{ProductID: id, Histogram: histogram([5]int{})}
+		}
+		statsList = append(statsList, s)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statsList)
+}
+
+// histogram expands per-star counts, indexed from one star, into buckets for every rating.
+func histogram(counts [5]int) []RatingBucket {
+	buckets := make([]RatingBucket, len(counts))
+	for i, c := range counts {
+		buckets[i] = RatingBucket{Stars: i + 1, Count: c}
+	}
+	return buckets
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"api/reviews/summary"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// summarySentences is the number of likes and of complaints kept per product.
+	summarySentences = 3
+
+	// summaryMaxReviews caps how many of a product's newest reviews are summarised.
+	summaryMaxReviews = 300
+
+	// summaryBatchSize is how many stale summaries the background job rebuilds per run.
+	summaryBatchSize = 100
+)
+
+// ReviewSummary is the cached summary of a product's approved reviews.
+type ReviewSummary struct {
+	ProductID string `json:"productId"`
+	summary.Summary
+	ReviewCount int    `json:"reviewCount"`
+	ComputedAt  string `json:"computedAt"`
+}
+
+// createSummariesTable stores one summary per product. review_count and
+// source_updated_at describe the reviews it was built from, so the summary can be
+// rebuilt once they change.
+func createSummariesTable() error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS review_summaries (
+			product_id VARCHAR(255) PRIMARY KEY,
+			likes JSONB NOT NULL,
+			complaints JSONB NOT NULL,
+			review_count INTEGER NOT NULL,
+			source_updated_at TIMESTAMP,
+			computed_at TIMESTAMP NOT NULL
+		)
+	`)
+	return err
+}
+
+// staleSummaries returns up to limit products whose summary is missing or was built
+// from a different set of approved reviews than the current one. A review counts as
+// changed when it is written, edited or moderated; deletions and restorations change
+// the number of reviews.
+func staleSummaries(limit int) ([]string, error) {
+	rows, err := db.Query(`
+		WITH live AS (
+			SELECT product_id, COUNT(*) AS review_count, MAX(GREATEST(created_at, edited_at, moderated_at)) AS updated_at
+			FROM reviews
+			WHERE status = $1 AND deleted_at IS NULL
+			GROUP BY product_id
+		)
+		SELECT COALESCE(live.product_id, s.product_id)
+		FROM live
+		FULL JOIN review_summaries s ON s.product_id = live.product_id
+		WHERE s.product_id IS NULL
+			OR COALESCE(live.review_count, 0) <> s.review_count
+			OR live.updated_at IS DISTINCT FROM s.source_updated_at
+		LIMIT $2`, StatusApproved, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stale summaries: %v", err)
+	}
+	defer rows.Close()
+
+	var productIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan product id: %v", err)
+		}
+		productIDs = append(productIDs, id)
+	}
+	return productIDs, rows.Err()
+}
+
+// summarizeProduct rebuilds the summary of a product from its newest approved reviews.
+func summarizeProduct(productID string) error {
+	// The source is described before the reviews are read, so a review written in
+	// between leaves the summary stale rather than marked up to date without it.
+	var count int
+	var updatedAt sql.NullTime
+	err := db.QueryRow(`
+		SELECT COUNT(*), MAX(GREATEST(created_at, edited_at, moderated_at))
+		FROM reviews WHERE product_id = $1 AND status = $2 AND deleted_at IS NULL`,
+		productID, StatusApproved,
+	).Scan(&count, &updatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to query review count: %v", err)
+	}
+
+	rows, err := db.Query(`
+		SELECT id, body FROM reviews
+		WHERE product_id = $1 AND status = $2 AND deleted_at IS NULL
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3`, productID, StatusApproved, summaryMaxReviews)
+	if err != nil {
+		return fmt.Errorf("failed to query reviews: %v", err)
+	}
+	defer rows.Close()
+
+	var reviews []summary.Review
+	for rows.Next() {
+		var r summary.Review
+		if err := rows.Scan(&r.ID, &r.Body); err != nil {
+			return fmt.Errorf("failed to scan review: %v", err)
+		}
+		reviews = append(reviews, r)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to query reviews: %v", err)
+	}
+
+	s := summary.Summarize(reviews, summarySentences)
+	likes, err := json.Marshal(s.Likes)
+	if err != nil {
+		return err
+	}
+	complaints, err := json.Marshal(s.Complaints)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO review_summaries (product_id, likes, complaints, review_count, source_updated_at, computed_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (product_id) DO UPDATE SET
+			likes = EXCLUDED.likes, complaints = EXCLUDED.complaints, review_count = EXCLUDED.review_count,
+			source_updated_at = EXCLUDED.source_updated_at, computed_at = EXCLUDED.computed_at`,
+		productID, likes, complaints, count, updatedAt, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store summary: %v", err)
+	}
+	return nil
+}
+
+// pruneSummary drops the sentences of a product's summary that are quoted from reviews
+// which are no longer approved, were deleted or no longer contain them, and marks the
+// summary stale so the background job rebuilds it. It is called inside the transaction
+// that hides, rejects, deletes or edits a review, so the summary never outlives the
+// review text it quotes.
+func pruneSummary(tx *sql.Tx, productID string) error {
+	_, err := tx.Exec(`
+		UPDATE review_summaries SET
+			likes = `+quotedSentences("likes")+`,
+			complaints = `+quotedSentences("complaints")+`,
+			review_count = -1
+		WHERE product_id = $1`,
+		productID, StatusApproved,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to prune review summary: %v", err)
+	}
+	return nil
+}
+
+// quotedSentences is the SQL expression keeping the sentences of a summary column whose
+// review is approved, not deleted and still contains them, in their original order.
+func quotedSentences(column string) string {
+	return `COALESCE((
+		SELECT jsonb_agg(s.sentence ORDER BY s.n)
+		FROM jsonb_array_elements(` + column + `) WITH ORDINALITY AS s(sentence, n)
+		WHERE EXISTS (
+			SELECT 1 FROM reviews r
+			WHERE r.id = s.sentence->>'reviewId' AND r.status = $2 AND r.deleted_at IS NULL
+				AND strpos(r.body, s.sentence->>'text') > 0
+		)
+	), '[]'::jsonb)`
+}
+
+// refreshSummaries rebuilds stale summaries until none are left and returns how many
+// were rebuilt.
+func refreshSummaries() (int, error) {
+	total := 0
+	for {
+		productIDs, err := staleSummaries(summaryBatchSize)
+		if err != nil {
+			return total, err
+		}
+		if len(productIDs) == 0 {
+			return total, nil
+		}
+		for _, id := range productIDs {
+			if err := summarizeProduct(id); err != nil {
+				return total, fmt.Errorf("failed to summarize product %s: %v", id, err)
+			}
+		}
+		total += len(productIDs)
+	}
+}
+
+// startSummaryJob keeps the cached summaries in step with new and changed reviews.
+// Summaries are rebuilt in the background because ranking sentences is too slow to do
+// while a review is being written; pruneSummary takes quotes down in the meantime.
+func startSummaryJob() {
+	interval := durationFromEnv("SUMMARY_INTERVAL", 5*time.Minute)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			n, err := refreshSummaries()
+			if err != nil {
+				log.Printf("Failed to refresh review summaries: %v\n", err)
+			}
+			if n > 0 {
+				log.Printf("Refreshed %d review summaries\n", n)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// getReviewSummaries returns the cached summary of each requested product in request
+// order, or null for products that have not been summarised yet.
+func getReviewSummaries(w http.ResponseWriter, r *http.Request) {
+	productIdsParam := r.URL.Query().Get("productIds")
+	if productIdsParam == "" {
+		http.Error(w, "productIds is required", http.StatusBadRequest)
+		return
+	}
+	productIds := strings.Split(productIdsParam, ",")
+
+	rows, err := db.Query(
+		"SELECT product_id, likes, complaints, review_count, computed_at FROM review_summaries WHERE product_id = ANY($1)",
+		pq.Array(productIds),
+	)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query review summaries: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	summaries := make(map[string]*ReviewSummary)
+	for rows.Next() {
+		var s ReviewSummary
+		var likes, complaints []byte
+		var computedAt time.Time
+		if err := rows.Scan(&s.ProductID, &likes, &complaints, &s.ReviewCount, &computedAt); err != nil {
+			http.Error(w, fmt.Sprintf("failed to scan review summary: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if err := json.Unmarshal(likes, &s.Likes); err != nil {
+			http.Error(w, fmt.Sprintf("failed to decode review summary: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if err := json.Unmarshal(complaints, &s.Complaints); err != nil {
+			http.Error(w, fmt.Sprintf("failed to decode review summary: %v", err), http.StatusInternalServerError)
+			return
+		}
+		s.ComputedAt = computedAt.Format(time.RFC3339)
+		summaries[s.ProductID] = &s
+	}
+
+	summariesList := make([]*ReviewSummary, 0, len(productIds))
+	for _, id := range productIds {
+		summariesList = append(summariesList, summaries[id])
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summariesList)
+}
+
+// summarizeReviews rebuilds stale summaries, or every summary with -all, without
+// waiting for the background job.
+func summarizeReviews(args []string) error {
+	fs := flag.NewFlagSet("summarize", flag.ContinueOnError)
+	all := fs.Bool("all", false, "rebuild every summary, not only stale ones")
+	productID := fs.String("product", "", "only rebuild the summary of this product")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *productID != "" {
+		if err := summarizeProduct(*productID); err != nil {
+			return err
+		}
+		fmt.Printf("Summary of product %s rebuilt\n", *productID)
+		return nil
+	}
+
+	if *all {
+		// Forgetting the sources makes every summary stale.
+		if _, err := db.Exec("UPDATE review_summaries SET review_count = -1"); err != nil {
+			return fmt.Errorf("failed to invalidate summaries: %v", err)
+		}
+	}
+
+	n, err := refreshSummaries()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Summaries complete: %d products summarised\n", n)
+	return nil
+}
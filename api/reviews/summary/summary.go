@@ -0,0 +1,224 @@
+// Package summary builds extractive summaries of a product's reviews. Sentences are
+// split by sentiment into likes and complaints, and within each group ranked with
+// TextRank over TF-IDF vectors, so the sentences picked are the ones most similar to
+// what other reviewers said.
+package summary
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+
+	"api/reviews/sentiment"
+)
+
+const (
+	// MinWords and MaxWords bound the sentences considered. Very short sentences say
+	// too little on their own and very long ones make poor summaries.
+	MinWords = 4
+	MaxWords = 40
+
+	// MaxCandidates caps the sentences ranked per group, since TextRank compares every
+	// pair. The earliest sentences, from the newest reviews, are kept.
+	MaxCandidates = 400
+
+	// opinionThreshold is how strongly a sentence must lean either way to count as a
+	// like or a complaint.
+	opinionThreshold = 0.2
+
+	// redundancyThreshold is the cosine similarity above which a sentence is considered
+	// to repeat one already picked.
+	redundancyThreshold = 0.2
+
+	damping       = 0.85
+	maxIterations = 50
+	tolerance     = 1e-6
+)
+
+// Review is a review to summarise.
+type Review struct {
+	ID   string
+	Body string
+}
+
+// Sentence is a sentence picked for a summary, with the review it was taken from.
+type Sentence struct {
+	Text     string `json:"text"`
+	ReviewID string `json:"reviewId"`
+}
+
+// Summary is the representative praise and criticism found in a set of reviews, most
+// representative first.
+type Summary struct {
+	Likes      []Sentence `json:"likes"`
+	Complaints []Sentence `json:"complaints"`
+}
+
+type candidate struct {
+	Sentence
+	terms  []string
+	vector map[string]float64
+}
+
+// Summarize picks up to n likes and n complaints from reviews, which should be ordered
+// newest first.
+func Summarize(reviews []Review, n int) Summary {
+	var likes, complaints []*candidate
+	for _, r := range reviews {
+		for _, s := range sentiment.Sentences(r.Body) {
+			s = strings.TrimSpace(s)
+			words := sentiment.Words(s)
+			if len(words) < MinWords || len(words) > MaxWords {
+				continue
+			}
+
+			c := &candidate{Sentence: Sentence{Text: s, ReviewID: r.ID}, terms: terms(words)}
+			switch score := sentiment.Analyze(s).Score; {
+			case score >= opinionThreshold && len(likes) < MaxCandidates:
+				likes = append(likes, c)
+			case score <= -opinionThreshold && len(complaints) < MaxCandidates:
+				complaints = append(complaints, c)
+			}
+		}
+	}
+
+	weigh(append(slices.Clip(likes), complaints...))
+	return Summary{Likes: pick(likes, n), Complaints: pick(complaints, n)}
+}
+
+// terms drops words too short to carry meaning. Common words that remain are
+// discounted by their inverse document frequency.
+func terms(words []string) []string {
+	kept := words[:0:0]
+	for _, w := range words {
+		if len(w) >= 3 {
+			kept = append(kept, w)
+		}
+	}
+	return kept
+}
+
+// weigh sets the TF-IDF vector of each candidate, normalised to unit length so cosine
+// similarity is a dot product. Document frequencies are counted over all candidates.
+func weigh(candidates []*candidate) {
+	df := make(map[string]int)
+	for _, c := range candidates {
+		seen := make(map[string]bool)
+		for _, t := range c.terms {
+			if !seen[t] {
+				seen[t] = true
+				df[t]++
+			}
+		}
+	}
+
+	total := float64(len(candidates))
+	for _, c := range candidates {
+		tf := make(map[string]float64)
+		for _, t := range c.terms {
+			tf[t]++
+		}
+
+		var norm float64
+		c.vector = make(map[string]float64, len(tf))
+		for t, f := range tf {
+			w := f * math.Log(1+total/float64(df[t]))
+			c.vector[t] = w
+			norm += w * w
+		}
+		norm = math.Sqrt(norm)
+		for t := range c.vector {
+			c.vector[t] /= norm
+		}
+	}
+}
+
+func similarity(a, b map[string]float64) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	var dot float64
+	for t, w := range a {
+		dot += w * b[t]
+	}
+	return dot
+}
+
+// pick ranks candidates with TextRank and returns the n best, skipping sentences that
+// repeat one already picked.
+func pick(candidates []*candidate, n int) []Sentence {
+	picked := []Sentence{}
+	if len(candidates) == 0 || n < 1 {
+		return picked
+	}
+
+	sim := make([][]float64, len(candidates))
+	for i := range sim {
+		sim[i] = make([]float64, len(candidates))
+	}
+	for i := range candidates {
+		for j := i + 1; j < len(candidates); j++ {
+			s := similarity(candidates[i].vector, candidates[j].vector)
+			sim[i][j], sim[j][i] = s, s
+		}
+	}
+
+	scores := rank(sim)
+	order := make([]int, len(candidates))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(scores[b], scores[a])
+	})
+
+	var chosen []int
+	for _, i := range order {
+		if len(chosen) == n {
+			break
+		}
+		if slices.ContainsFunc(chosen, func(j int) bool { return sim[i][j] > redundancyThreshold }) {
+			continue
+		}
+		chosen = append(chosen, i)
+		picked = append(picked, candidates[i].Sentence)
+	}
+	return picked
+}
+
+// rank runs PageRank over the weighted similarity graph. A sentence scores highly when
+// it resembles many other sentences that themselves score highly.
+func rank(sim [][]float64) []float64 {
+	n := len(sim)
+	out := make([]float64, n)
+	for i := range sim {
+		for _, s := range sim[i] {
+			out[i] += s
+		}
+	}
+
+	scores := make([]float64, n)
+	for i := range scores {
+		scores[i] = 1 / float64(n)
+	}
+	next := make([]float64, n)
+	for range maxIterations {
+		var delta float64
+		for i := range next {
+			var sum float64
+			for j := range sim {
+				if out[j] > 0 {
+					sum += sim[j][i] / out[j] * scores[j]
+				}
+			}
+			next[i] = (1-damping)/float64(n) + damping*sum
+			delta += math.Abs(next[i] - scores[i])
+		}
+		scores, next = next, scores
+		if delta < tolerance {
+			break
+		}
+	}
+	return scores
+}
@@ -0,0 +1,94 @@
+package summary
+
+import (
+	"slices"
+	"testing"
+)
+
+var reviews = []Review{
+	{ID: "r1", Body: "The battery life is excellent and lasts all day. The box was nice."},
+	{ID: "r2", Body: "Battery life is excellent, it easily lasts all day."},
+	{ID: "r3", Body: "I love the bright colors of the case design."},
+	{ID: "r4", Body: "The excellent battery lasts all day long. The keys broke after a week of typing."},
+	{ID: "r5", Body: "Sadly the keys broke after only a week. Terrible support too."},
+	{ID: "r6", Body: "The keys broke within a week of light typing."},
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name       string
+		reviews    []Review
+		n          int
+		likes      []Sentence
+		complaints []Sentence
+	}{
+		{
+			name:       "most representative sentence of each group",
+			reviews:    reviews,
+			n:          1,
+			likes:      []Sentence{{Text: "The battery life is excellent and lasts all day", ReviewID: "r1"}},
+			complaints: []Sentence{{Text: "The keys broke after a week of typing", ReviewID: "r4"}},
+		},
+		{
+			// The other battery and broken key sentences repeat the ones already picked.
+			name:    "redundant sentences are skipped",
+			reviews: reviews,
+			n:       3,
+			likes: []Sentence{
+				{Text: "The battery life is excellent and lasts all day", ReviewID: "r1"},
+				{Text: "I love the bright colors of the case design", ReviewID: "r3"},
+				{Text: "The box was nice", ReviewID: "r1"},
+			},
+			complaints: []Sentence{{Text: "The keys broke after a week of typing", ReviewID: "r4"}},
+		},
+		{
+			name:       "no sentences requested",
+			reviews:    reviews,
+			n:          0,
+			likes:      []Sentence{},
+			complaints: []Sentence{},
+		},
+		{
+			name:       "no opinions",
+			reviews:    []Review{{ID: "r1", Body: "It is a keyboard with keys on it. Ok."}},
+			n:          3,
+			likes:      []Sentence{},
+			complaints: []Sentence{},
+		},
+	}
+	for _, tt := range tests {
+		got := Summarize(tt.reviews, tt.n)
+		if !slices.Equal(got.Likes, tt.likes) {
+			t.Errorf("%s: likes = %+v, want %+v", tt.name, got.Likes, tt.likes)
+		}
+		if !slices.Equal(got.Complaints, tt.complaints) {
+			t.Errorf("%s: complaints = %+v, want %+v", tt.name, got.Complaints, tt.complaints)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	tests := []struct {
+		name string
+		sim  [][]float64
+		best int
+	}{
+		{"hub of a star", [][]float64{{0, 1, 1}, {1, 0, 0}, {1, 0, 0}}, 0},
+		{"strongest ties", [][]float64{{0, 0.1, 0.1}, {0.1, 0, 0.9}, {0.1, 0.9, 0}}, 1},
+	}
+	for _, tt := range tests {
+		scores := rank(tt.sim)
+		var sum float64
+		for _, s := range scores {
+			sum += s
+		}
+		if sum < 0.99 || sum > 1.01 {
+			t.Errorf("%s: scores %v sum to %f, want 1", tt.name, scores, sum)
+		}
+		for i, s := range scores {
+			if i != tt.best && s > scores[tt.best] {
+				t.Errorf("%s: scores = %v, want %d ranked highest", tt.name, scores, tt.best)
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// reviewUniqueIndex allows a user at most one live review per product. Deleted reviews
+// don't count, so a user can write a new review after deleting the old one. Rejected and
+// hidden reviews do count: their authors edit them instead, which screens the text again.
+const reviewUniqueIndex = "reviews_product_user_key"
+
+// ReviewConflict is the body of the 409 returned when a user has already reviewed the
+// product, so clients can find and edit the existing review instead.
+type ReviewConflict struct {
+	Error     string `json:"error"`
+	Code      string `json:"code"`
+	ReviewID  string `json:"reviewId"`
+	ProductID string `json:"productId"`
+	UserID    string `json:"userId"`
+}
+
+// migrateUniqueReviews builds the one-review-per-user-per-product index. Duplicates
+// written before the index existed are resolved first by keeping the newest review
+// and soft-deleting the others.
+func migrateUniqueReviews() error {
+	res, err := db.Exec(`
+		UPDATE reviews r SET deleted_at = $1
+		WHERE r.deleted_at IS NULL AND EXISTS (
+			SELECT 1 FROM reviews n
+			WHERE n.product_id = r.product_id AND n.user_id = r.user_id AND n.deleted_at IS NULL
+				AND (n.created_at, n.id) > (r.created_at, r.id)
+		)`,
+		time.Now().UTC(),
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		log.Printf("Soft-deleted %d duplicate reviews\n", n)
+	}
+
+	_, err = db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS " + reviewUniqueIndex + " ON reviews (product_id, user_id) WHERE deleted_at IS NULL")
+	return err
+}
+
+// isReviewConflict reports whether err was caused by the one-review-per-user-per-product
+// index.
+func isReviewConflict(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == reviewUniqueIndex
+}
+
+// writeReviewConflict responds with 409 and the ID of the user's existing review.
+func writeReviewConflict(w http.ResponseWriter, productID, userID string) {
+	conflict := ReviewConflict{
+		Error:     "user has already reviewed this product",
+		Code:      "REVIEW_EXISTS",
+		ProductID: productID,
+		UserID:    userID,
+	}
+	err := db.QueryRow(
+		"SELECT id FROM reviews WHERE product_id = $1 AND user_id = $2 AND deleted_at IS NULL",
+		productID, userID,
+	).Scan(&conflict.ReviewID)
+	if err != nil && err != sql.ErrNoRows {
+		http.Error(w, fmt.Sprintf("failed to query existing review: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(conflict)
+}
+
+// upsertReview creates the user's review of a product, or replaces the body and rating
+// of the one they already wrote. Replacing records a new revision and screens the new
+// text like any other edit, so a rejected review with new text goes back to moderation.
+func upsertReview(w http.ResponseWriter, r *http.Request) {
+	productID := r.PathValue("productId")
+	userID := r.PathValue("userId")
+
+	var input struct {
+		Body   string `json:"body"`
+		Rating int    `json:"rating"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if input.Rating < 1 || input.Rating > 5 {
+		http.Error(w, "rating must be between 1 and 5", http.StatusBadRequest)
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to begin transaction: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	status := http.StatusOK
+	current, err := scanReview(tx.QueryRow(
+		"SELECT "+reviewColumns+" FROM reviews WHERE product_id = $1 AND user_id = $2 AND deleted_at IS NULL FOR UPDATE",
+		productID, userID,
+	))
+	var rev Review
+	switch {
+	case err == sql.ErrNoRows:
+		rev = Review{ProductID: productID, UserID: userID, Body: input.Body, Rating: input.Rating}
+		err = insertReview(tx, &rev)
+		status = http.StatusCreated
+	case err != nil:
+		err = fmt.Errorf("failed to query review: %v", err)
+	default:
+		rev, err = editReview(tx, current, input.Body, input.Rating, userID)
+	}
+	// A concurrent request created the review between the lookup and the insert.
+	if isReviewConflict(err) {
+		writeReviewConflict(w, productID, userID)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to commit review: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(rev)
+}
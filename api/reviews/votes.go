@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type VoteCounts struct {
+	ReviewID       string `json:"reviewId"`
+	HelpfulCount   int    `json:"helpfulCount"`
+	UnhelpfulCount int    `json:"unhelpfulCount"`
+}
+
+// createVotesTable stores at most one helpful/not-helpful vote per user per review.
+func createVotesTable() error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS review_votes (
+			review_id VARCHAR(255) NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+			user_id VARCHAR(255) NOT NULL,
+			helpful BOOLEAN NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (review_id, user_id)
+		)
+	`)
+	return err
+}
+
+// castVote records the user's vote on an approved review, replacing any earlier vote by
+// the same user.
+func castVote(w http.ResponseWriter, r *http.Request) {
+	reviewID := r.PathValue("id")
+	userID := r.PathValue("userId")
+
+	var vote struct {
+		Helpful *bool `json:"helpful"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&vote); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if vote.Helpful == nil {
+		http.Error(w, "helpful is required", http.StatusBadRequest)
+		return
+	}
+
+	res, err := db.Exec(`
+		INSERT INTO review_votes (review_id, user_id, helpful, created_at)
+		SELECT id, $2, $3, $4 FROM reviews WHERE id = $1 AND status = $5 AND deleted_at IS NULL
+		ON CONFLICT (review_id, user_id) DO UPDATE SET helpful = EXCLUDED.helpful, created_at = EXCLUDED.created_at`,
+		reviewID, userID, *vote.Helpful, time.Now().UTC(), StatusApproved,
+	)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to record vote: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to check rows affected: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if rowsAffected == 0 {
+		http.Error(w, "review not found", http.StatusNotFound)
+		return
+	}
+
+	writeVoteCounts(w, reviewID)
+}
+
+// retractVote removes the user's vote on a review.
+func retractVote(w http.ResponseWriter, r *http.Request) {
+	reviewID := r.PathValue("id")
+	userID := r.PathValue("userId")
+
+	res, err := db.Exec("DELETE FROM review_votes WHERE review_id = $1 AND user_id = $2", reviewID, userID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to retract vote: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to check rows affected: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if rowsAffected == 0 {
+		http.Error(w, "vote not found", http.StatusNotFound)
+		return
+	}
+
+	writeVoteCounts(w, reviewID)
+}
+
+// getVoteCounts returns helpful/not-helpful totals for each review in reviewIds, in
+// request order. Reviews without votes are reported with zero counts.
+func getVoteCounts(w http.ResponseWriter, r *http.Request) {
+	reviewIdsParam := r.URL.Query().Get("reviewIds")
+	if reviewIdsParam == "" {
+		http.Error(w, "reviewIds is required", http.StatusBadRequest)
+		return
+	}
+	reviewIds := strings.Split(reviewIdsParam, ",")
+
+	countsList, err := queryVoteCounts(reviewIds)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(countsList)
+}
+
+func writeVoteCounts(w http.ResponseWriter, reviewID string) {
+	countsList, err := queryVoteCounts([]string{reviewID})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(countsList[0])
+}
+
+func queryVoteCounts(reviewIds []string) ([]VoteCounts, error) {
+	rows, err := db.Query(`
+		SELECT review_id, COUNT(*) FILTER (WHERE helpful), COUNT(*) FILTER (WHERE NOT helpful)
+		FROM review_votes
+		WHERE review_id = ANY($1)
+		GROUP BY review_id`, pq.Array(reviewIds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query vote counts: %v", err)
+	}
+	defer rows.Close()
+
+	countsMap := make(map[string]VoteCounts)
+	for rows.Next() {
+		var c VoteCounts
+		if err := rows.Scan(&c.ReviewID, &c.HelpfulCount, &c.UnhelpfulCount); err != nil {
+			return nil, fmt.Errorf("failed to scan vote counts: %v", err)
+		}
+		countsMap[c.ReviewID] = c
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read vote counts: %v", err)
+	}
+
+	countsList := make([]VoteCounts, 0, len(reviewIds))
+	for _, id := range reviewIds {
+		c, found := countsMap[id]
+		if !found {
+			c = VoteCounts{ReviewID: id}
+		}
+		countsList = append(countsList, c)
+	}
+	return countsList, nil
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// TokenResponse is returned by login and refresh. The refresh token is opaque and can be
+// used once: refreshing returns a new one in its place.
+type TokenResponse struct {
+	AccessToken  string `json:"accessToken"`
+	TokenType    string `json:"tokenType"`
+	ExpiresIn    int64  `json:"expiresIn"`
+	RefreshToken string `json:"refreshToken"`
+	User         User   `json:"user"`
+}
+
+// createRefreshTokensTable stores refresh tokens by the SHA-256 of their value, so a
+// leaked table can't be used to log in. Tokens descending from the same login share a
+// family, which is revoked as a whole on logout or when a used token is replayed.
+func createRefreshTokensTable() error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS refresh_tokens (
+			id VARCHAR(255) PRIMARY KEY,
+			user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			family_id VARCHAR(255) NOT NULL,
+			token_hash VARCHAR(64) NOT NULL UNIQUE,
+			created_at TIMESTAMP NOT NULL,
+			expires_at TIMESTAMP NOT NULL,
+			used_at TIMESTAMP,
+			revoked_at TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS refresh_tokens_family_idx ON refresh_tokens (family_id)")
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS refresh_tokens_user_idx ON refresh_tokens (user_id)")
+	return err
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// insertRefreshToken creates a refresh token in family inside tx and returns its value.
+func insertRefreshToken(tx *sql.Tx, userID, familyID string) (string, error) {
+	b := make([]byte, 32)
+	rand.Read(b)
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	now := time.Now().UTC()
+	_, err := tx.Exec(`
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		generateID(), userID, familyID, hashRefreshToken(token), now, now.Add(tokenConfig.refreshTTL),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to store refresh token: %v", err)
+	}
+	return token, nil
+}
+
+// revokeUserSessions revokes every refresh token of the user inside tx. Access tokens
+// already issued stay valid until they expire.
+func revokeUserSessions(tx *sql.Tx, userID string) error {
+	_, err := tx.Exec("UPDATE refresh_tokens SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL", time.Now().UTC(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %v", err)
+	}
+	return nil
+}
+
+func revokeFamily(tx *sql.Tx, familyID string) error {
+	_, err := tx.Exec("UPDATE refresh_tokens SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL", time.Now().UTC(), familyID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %v", err)
+	}
+	return nil
+}
+
+// issueTokens commits tx after adding a refresh token to family, then writes both tokens.
+func issueTokens(w http.ResponseWriter, tx *sql.Tx, user User, roles []string, familyID string) {
+	refreshToken, err := insertRefreshToken(tx, user.ID, familyID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	accessToken, err := issueAccessToken(user.ID, user.Username, roles)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to sign access token: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to commit tokens: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(TokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(tokenConfig.accessTTL / time.Second),
+		RefreshToken: refreshToken,
+		User:         user,
+	})
+}
+
+// login checks a username and password and starts a new session. Unknown usernames,
+// deleted users, users without a password and wrong passwords all get the same 401.
+func login(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var user User
+	var roles []string
+	var hash sql.NullString
+	err := db.QueryRow(`
+		SELECT u.id, u.username, u.roles, c.password_hash FROM users u
+		LEFT JOIN user_credentials c ON c.user_id = u.id
+		WHERE lower(u.username) = lower($1) AND u.deleted_at IS NULL`,
+		input.Username,
+	).Scan(&user.ID, &user.Username, pq.Array(&roles), &hash)
+	if err != nil && err != sql.ErrNoRows {
+		http.Error(w, fmt.Sprintf("failed to query user: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if !hash.Valid {
+		verifyPassword(dummyHash(), input.Password)
+		writeAuthError(w, http.StatusUnauthorized, CodeInvalidCredentials, "invalid username or password")
+		return
+	}
+
+	ok, rehash, err := verifyPassword(hash.String, input.Password)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to verify password: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		writeAuthError(w, http.StatusUnauthorized, CodeInvalidCredentials, "invalid username or password")
+		return
+	}
+
+	if rehash {
+		_, err := db.Exec("UPDATE user_credentials SET password_hash = $1, updated_at = $2 WHERE user_id = $3 AND password_hash = $4",
+			hashPassword(input.Password), time.Now().UTC(), user.ID, hash.String)
+		if err != nil {
+			log.Printf("Failed to upgrade password hash of user %s: %v\n", user.ID, err)
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to begin transaction: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	issueTokens(w, tx, user, roles, generateID())
+}
+
+// refresh exchanges a refresh token for a new access and refresh token. A token that was
+// already exchanged must have been copied, so presenting it again revokes its whole
+// family and both the thief and the user have to log in again.
+func refresh(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to begin transaction: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	var tokenID, familyID string
+	var expiresAt time.Time
+	var usedAt, revokedAt, deletedAt sql.NullTime
+	var user User
+	var roles []string
+	err = tx.QueryRow(`
+		SELECT t.id, t.family_id, t.expires_at, t.used_at, t.revoked_at, u.id, u.username, u.roles, u.deleted_at
+		FROM refresh_tokens t JOIN users u ON u.id = t.user_id
+		WHERE t.token_hash = $1
+		FOR UPDATE OF t`,
+		hashRefreshToken(input.RefreshToken),
+	).Scan(&tokenID, &familyID, &expiresAt, &usedAt, &revokedAt, &user.ID, &user.Username, pq.Array(&roles), &deletedAt)
+	if err == sql.ErrNoRows {
+		writeAuthError(w, http.StatusUnauthorized, CodeInvalidRefreshToken, "invalid refresh token")
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query refresh token: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if usedAt.Valid && !revokedAt.Valid {
+		log.Printf("Refresh token of user %s was reused, revoking session %s\n", user.ID, familyID)
+		if err := revokeFamily(tx, familyID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := tx.Commit(); err != nil {
+			http.Error(w, fmt.Sprintf("failed to commit revocation: %v", err), http.StatusInternalServerError)
+			return
+		}
+		writeAuthError(w, http.StatusUnauthorized, CodeInvalidRefreshToken, "invalid refresh token")
+		return
+	}
+	if usedAt.Valid || revokedAt.Valid || deletedAt.Valid || time.Now().UTC().After(expiresAt) {
+		writeAuthError(w, http.StatusUnauthorized, CodeInvalidRefreshToken, "invalid refresh token")
+		return
+	}
+
+	if _, err := tx.Exec("UPDATE refresh_tokens SET used_at = $1 WHERE id = $2", time.Now().UTC(), tokenID); err != nil {
+		http.Error(w, fmt.Sprintf("failed to rotate refresh token: %v", err), http.StatusInternalServerError)
+		return
+	}
+	issueTokens(w, tx, user, roles, familyID)
+}
+
+// revoke ends the session a refresh token belongs to. Unknown tokens are accepted
+// silently, as in RFC 7009, so logging out twice is harmless.
+func revoke(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	_, err := db.Exec(`
+		UPDATE refresh_tokens SET revoked_at = $1
+		WHERE revoked_at IS NULL AND family_id = (SELECT family_id FROM refresh_tokens WHERE token_hash = $2)`,
+		time.Now().UTC(), hashRefreshToken(input.RefreshToken),
+	)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to revoke session: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// revokeSessions logs a user out everywhere.
+func revokeSessions(w http.ResponseWriter, r *http.Request) {
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to begin transaction: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	if err := revokeUserSessions(tx, r.PathValue("id")); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to commit revocation: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -3,3 +3,8 @@ module api/users
 go 1.24.0
 
 require github.com/lib/pq v1.11.2
+
+require (
+	golang.org/x/crypto v0.45.0
+	golang.org/x/sys v0.38.0 // indirect
+)
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/lib/pq"
 )
@@ -55,13 +56,52 @@ func main() {
 		log.Fatalf("Failed to create users table: %v\n", err)
 	}
 
+	// Deleted users are kept for a retention period so deletes can be undone.
+	_, err = db.Exec("ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP")
+	if err != nil {
+		log.Fatalf("Failed to add deleted_at column: %v\n", err)
+	}
+
+	if err = migrateUniqueUsernames(); err != nil {
+		log.Fatalf("Failed to create username unique index: %v\n", err)
+	}
+
+	// Roles are granted directly in the database and carried in access tokens.
+	_, err = db.Exec("ALTER TABLE users ADD COLUMN IF NOT EXISTS roles TEXT[] NOT NULL DEFAULT '{}'")
+	if err != nil {
+		log.Fatalf("Failed to add roles column: %v\n", err)
+	}
+
+	if err = createCredentialsTable(); err != nil {
+		log.Fatalf("Failed to create user_credentials table: %v\n", err)
+	}
+
+	if err = createRefreshTokensTable(); err != nil {
+		log.Fatalf("Failed to create refresh_tokens table: %v\n", err)
+	}
+
+	if err = loadTokenConfig(); err != nil {
+		log.Fatalf("Failed to load JWT signing key: %v\n", err)
+	}
+
+	startPurgeJob()
+
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("POST /users", createUser)
 	mux.HandleFunc("GET /users", getAllUsers)
 	mux.HandleFunc("GET /users/{id}", getUserByID)
+	mux.HandleFunc("GET /users/by-username/{username}", getUserByUsername)
 	mux.HandleFunc("PUT /users/{id}", updateUser)
 	mux.HandleFunc("DELETE /users/{id}", deleteUser)
+	mux.HandleFunc("PUT /users/{id}/password", setPassword)
+	mux.HandleFunc("DELETE /users/{id}/sessions", revokeSessions)
+	mux.HandleFunc("POST /auth/login", login)
+	mux.HandleFunc("POST /auth/refresh", refresh)
+	mux.HandleFunc("POST /auth/revoke", revoke)
+	mux.HandleFunc("GET /.well-known/jwks.json", getJWKS)
+	// Admin endpoints
+	mux.HandleFunc("POST /users/{id}/restore", restoreUser)
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -72,22 +112,59 @@ func main() {
 	log.Fatal(http.ListenAndServe(":"+port, mux))
 }
 
+// createUser creates a user, with a password if one is given. Users without a password
+// can't log in until one is set.
 func createUser(w http.ResponseWriter, r *http.Request) {
-	var user User
-	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+	var input struct {
+		User
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	user := input.User
+
+	if usernameErr := checkUsername(user.Username); usernameErr != nil {
+		writeUsernameError(w, http.StatusBadRequest, usernameErr)
+		return
+	}
+	if input.Password != "" {
+		if msg := checkPassword(input.Password); msg != "" {
+			writeAuthError(w, http.StatusBadRequest, CodeWeakPassword, msg)
+			return
+		}
+	}
 
 	if user.ID == "" {
 		user.ID = generateID()
 	}
 
-	_, err := db.Exec("INSERT INTO users (id, username) VALUES ($1, $2)", user.ID, user.Username)
+	tx, err := db.Begin()
 	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to begin transaction: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("INSERT INTO users (id, username) VALUES ($1, $2)", user.ID, user.Username)
+	if isUsernameConflict(err) {
+		writeUsernameConflict(w, user.Username)
+		return
+	} else if err != nil {
 		http.Error(w, fmt.Sprintf("failed to insert user: %v", err), http.StatusInternalServerError)
 		return
 	}
+	if input.Password != "" {
+		if err := storePassword(tx, user.ID, input.Password); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to commit user: %v", err), http.StatusInternalServerError)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -101,9 +178,9 @@ func getAllUsers(w http.ResponseWriter, r *http.Request) {
 
 	if idsParam != "" {
 		ids := strings.Split(idsParam, ",")
-		rows, err = db.Query("SELECT id, username FROM users WHERE id = ANY($1)", pq.Array(ids))
+		rows, err = db.Query("SELECT id, username FROM users WHERE id = ANY($1) AND deleted_at IS NULL", pq.Array(ids))
 	} else {
-		rows, err = db.Query("SELECT id, username FROM users")
+		rows, err = db.Query("SELECT id, username FROM users WHERE deleted_at IS NULL")
 	}
 
 	if err != nil {
@@ -134,7 +211,7 @@ func getUserByID(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 
 	var user User
-	err := db.QueryRow("SELECT id, username FROM users WHERE id = $1", id).Scan(&user.ID, &user.Username)
+	err := db.QueryRow("SELECT id, username FROM users WHERE id = $1 AND deleted_at IS NULL", id).Scan(&user.ID, &user.Username)
 	if err == sql.ErrNoRows {
 		http.Error(w, "user not found", http.StatusNotFound)
 		return
@@ -156,8 +233,16 @@ func updateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	res, err := db.Exec("UPDATE users SET username = $1 WHERE id = $2", updatedUser.Username, id)
-	if err != nil {
+	if usernameErr := checkUsername(updatedUser.Username); usernameErr != nil {
+		writeUsernameError(w, http.StatusBadRequest, usernameErr)
+		return
+	}
+
+	res, err := db.Exec("UPDATE users SET username = $1 WHERE id = $2 AND deleted_at IS NULL", updatedUser.Username, id)
+	if isUsernameConflict(err) {
+		writeUsernameConflict(w, updatedUser.Username)
+		return
+	} else if err != nil {
 		http.Error(w, fmt.Sprintf("failed to update user: %v", err), http.StatusInternalServerError)
 		return
 	}
@@ -178,10 +263,18 @@ func updateUser(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(updatedUser)
 }
 
+// deleteUser soft deletes a user and ends all their sessions.
 func deleteUser(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 
-	res, err := db.Exec("DELETE FROM users WHERE id = $1", id)
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to begin transaction: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("UPDATE users SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL", time.Now().UTC(), id)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("failed to delete user: %v", err), http.StatusInternalServerError)
 		return
@@ -198,5 +291,69 @@ func deleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := revokeUserSessions(tx, id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to commit delete: %v", err), http.StatusInternalServerError)
+		return
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func restoreUser(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var user User
+	err := db.QueryRow("UPDATE users SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING id, username", id).
+		Scan(&user.ID, &user.Username)
+	if err == sql.ErrNoRows {
+		http.Error(w, "deleted user not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("failed to restore user: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// startPurgeJob permanently removes users that have been deleted for longer than
+// PURGE_RETENTION (default 30 days), checking every PURGE_INTERVAL (default 1 hour).
+// Expired refresh tokens are removed at the same time.
+func startPurgeJob() {
+	retention := durationFromEnv("PURGE_RETENTION", 30*24*time.Hour)
+	interval := durationFromEnv("PURGE_INTERVAL", time.Hour)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			res, err := db.Exec("DELETE FROM users WHERE deleted_at < $1", time.Now().UTC().Add(-retention))
+			if err != nil {
+				log.Printf("Failed to purge deleted users: %v\n", err)
+			} else if n, _ := res.RowsAffected(); n > 0 {
+				log.Printf("Purged %d deleted users\n", n)
+			}
+			if _, err := db.Exec("DELETE FROM refresh_tokens WHERE expires_at < $1", time.Now().UTC()); err != nil {
+				log.Printf("Failed to purge expired refresh tokens: %v\n", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Fatalf("Invalid %s %q: expected a positive duration such as 720h\n", name, v)
+	}
+	return d
+}
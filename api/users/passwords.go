@@ -0,0 +1,216 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// argon2id parameters for new password hashes, following the second recommended option
+// of RFC 9106. Hashes made with other parameters are upgraded on the next login.
+const (
+	argonTime    = 3
+	argonMemory  = 64 * 1024
+	argonThreads = 2
+	argonKeyLen  = 32
+	argonSaltLen = 16
+)
+
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 128
+)
+
+// Codes reported in AuthError.
+const (
+	CodeWeakPassword        = "WEAK_PASSWORD"
+	CodeInvalidCredentials  = "INVALID_CREDENTIALS"
+	CodeInvalidRefreshToken = "INVALID_REFRESH_TOKEN"
+)
+
+// AuthError is the body of the 400 and 401 responses of password and token endpoints.
+type AuthError struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+func writeAuthError(w http.ResponseWriter, status int, code, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(AuthError{Error: msg, Code: code})
+}
+
+// createCredentialsTable stores password hashes, one per user. Users created before
+// passwords existed have none until one is set.
+func createCredentialsTable() error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS user_credentials (
+			user_id VARCHAR(255) PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+			password_hash TEXT NOT NULL,
+			updated_at TIMESTAMP NOT NULL
+		)
+	`)
+	return err
+}
+
+// checkPassword returns why password can't be used, or an empty string if it can.
+func checkPassword(password string) string {
+	if n := utf8.RuneCountInString(password); n < minPasswordLength || n > maxPasswordLength {
+		return fmt.Sprintf("password must be %d to %d characters", minPasswordLength, maxPasswordLength)
+	}
+	return ""
+}
+
+// hashPassword hashes password with argon2id, encoded in the PHC string format.
+func hashPassword(password string) string {
+	salt := make([]byte, argonSaltLen)
+	rand.Read(salt)
+	key := argon2.IDKey([]byte(password), salt, argonTime, argonMemory, argonThreads, argonKeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, argonMemory, argonTime, argonThreads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+}
+
+// verifyPassword checks password against an argon2id or bcrypt hash. rehash is set when
+// the password is correct but the hash should be replaced with one made by hashPassword,
+// such as bcrypt hashes of imported accounts.
+func verifyPassword(hash, password string) (ok, rehash bool, err error) {
+	if strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$") {
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, false, nil
+		}
+		return err == nil, true, err
+	}
+
+	var version int
+	var memory, time uint32
+	var threads uint8
+	var salt, key string
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false, false, errors.New("unsupported password hash")
+	}
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, false, errors.New("unsupported argon2 version")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false, false, errors.New("malformed argon2 parameters")
+	}
+	salt, key = parts[4], parts[5]
+	saltBytes, err := base64.RawStdEncoding.DecodeString(salt)
+	if err != nil {
+		return false, false, errors.New("malformed argon2 salt")
+	}
+	keyBytes, err := base64.RawStdEncoding.DecodeString(key)
+	if err != nil {
+		return false, false, errors.New("malformed argon2 hash")
+	}
+
+	computed := argon2.IDKey([]byte(password), saltBytes, time, memory, threads, uint32(len(keyBytes)))
+	if subtle.ConstantTimeCompare(computed, keyBytes) != 1 {
+		return false, false, nil
+	}
+	rehash = memory != argonMemory || time != argonTime || threads != argonThreads || len(keyBytes) != argonKeyLen
+	return true, rehash, nil
+}
+
+// dummyHash is checked against when a login names an unknown user, so that the response
+// takes as long as for a wrong password and doesn't reveal which usernames exist.
+var dummyHash = sync.OnceValue(func() string {
+	return hashPassword(generateID())
+})
+
+// storePassword sets the user's password hash inside tx.
+func storePassword(tx *sql.Tx, userID, password string) error {
+	_, err := tx.Exec(`
+		INSERT INTO user_credentials (user_id, password_hash, updated_at) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET password_hash = EXCLUDED.password_hash, updated_at = EXCLUDED.updated_at`,
+		userID, hashPassword(password), time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store password: %v", err)
+	}
+	return nil
+}
+
+// setPassword changes a user's password. The current password is required once the
+// user has one. Every session of the user is revoked, so stolen refresh tokens stop
+// working.
+func setPassword(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("id")
+
+	var input struct {
+		CurrentPassword string `json:"currentPassword"`
+		Password        string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if msg := checkPassword(input.Password); msg != "" {
+		writeAuthError(w, http.StatusBadRequest, CodeWeakPassword, msg)
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to begin transaction: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	var hash sql.NullString
+	err = tx.QueryRow(`
+		SELECT c.password_hash FROM users u
+		LEFT JOIN user_credentials c ON c.user_id = u.id
+		WHERE u.id = $1 AND u.deleted_at IS NULL
+		FOR UPDATE OF u`,
+		userID,
+	).Scan(&hash)
+	if err == sql.ErrNoRows {
+		http.Error(w, "user not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query credentials: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if hash.Valid {
+		ok, _, err := verifyPassword(hash.String, input.CurrentPassword)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to verify password: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			writeAuthError(w, http.StatusUnauthorized, CodeInvalidCredentials, "current password is incorrect")
+			return
+		}
+	}
+
+	if err := storePassword(tx, userID, input.Password); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := revokeUserSessions(tx, userID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		http.Error(w, fmt.Sprintf("failed to commit password: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// JWK is an RSA public key as published in the JWKS document.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// Claims are the claims of an access token. Subgraphs trust sub as the caller's user
+// ID and roles as the roles granted to them.
+type Claims struct {
+	Issuer    string   `json:"iss"`
+	Audience  string   `json:"aud"`
+	Subject   string   `json:"sub"`
+	IssuedAt  int64    `json:"iat"`
+	ExpiresAt int64    `json:"exp"`
+	ID        string   `json:"jti"`
+	Username  string   `json:"username"`
+	Roles     []string `json:"roles"`
+}
+
+// tokenConfig holds the signing key and the settings of issued tokens, loaded once at
+// startup by loadTokenConfig.
+var tokenConfig struct {
+	key        *rsa.PrivateKey
+	kid        string
+	jwks       []JWK
+	issuer     string
+	audience   string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// loadTokenConfig reads the signing key from the PEM file named by JWT_SIGNING_KEY_FILE.
+// Keys listed in JWT_RETIRED_KEY_FILES, comma separated, are still published so tokens
+// they signed stay valid until they expire after a key rotation. Without a signing key
+// an ephemeral one is generated, which is fine for development but logs everyone out on
+// every restart.
+func loadTokenConfig() error {
+	tokenConfig.issuer = os.Getenv("JWT_ISSUER")
+	if tokenConfig.issuer == "" {
+		tokenConfig.issuer = "http://localhost:8080"
+	}
+	tokenConfig.audience = os.Getenv("JWT_AUDIENCE")
+	if tokenConfig.audience == "" {
+		tokenConfig.audience = "product-reviews"
+	}
+	tokenConfig.accessTTL = durationFromEnv("ACCESS_TOKEN_TTL", 15*time.Minute)
+	tokenConfig.refreshTTL = durationFromEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+
+	if path := os.Getenv("JWT_SIGNING_KEY_FILE"); path != "" {
+		_, priv, err := readRSAKey(path)
+		if err != nil {
+			return err
+		}
+		if priv == nil {
+			return fmt.Errorf("%s holds a public key, a private key is needed for signing", path)
+		}
+		tokenConfig.key = priv
+	} else {
+		log.Println("JWT_SIGNING_KEY_FILE is not set, signing tokens with an ephemeral key")
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return err
+		}
+		tokenConfig.key = key
+	}
+	current := rsaJWK(&tokenConfig.key.PublicKey)
+	tokenConfig.kid = current.Kid
+	tokenConfig.jwks = []JWK{current}
+
+	for _, path := range strings.Split(os.Getenv("JWT_RETIRED_KEY_FILES"), ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		pub, _, err := readRSAKey(path)
+		if err != nil {
+			return err
+		}
+		if retired := rsaJWK(pub); retired.Kid != current.Kid {
+			tokenConfig.jwks = append(tokenConfig.jwks, retired)
+		}
+	}
+	return nil
+}
+
+// readRSAKey reads an RSA key from a PEM file. It accepts PKCS#8 and PKCS#1 private keys,
+// as written by openssl genpkey and openssl genrsa, and PKIX public keys. priv is nil for
+// public keys.
+func readRSAKey(path string) (pub *rsa.PublicKey, priv *rsa.PrivateKey, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, nil, fmt.Errorf("%s is not a PEM file", path)
+	}
+
+	var key any
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		err = fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return &k.PublicKey, k, nil
+	case *rsa.PublicKey:
+		return k, nil, nil
+	}
+	return nil, nil, fmt.Errorf("%s does not hold an RSA key", path)
+}
+
+// rsaJWK converts pub to a JWK whose key ID is its RFC 7638 thumbprint, so the same key
+// always gets the same ID.
+func rsaJWK(pub *rsa.PublicKey) JWK {
+	n := base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	thumbprint := sha256.Sum256([]byte(`{"e":"` + e + `","kty":"RSA","n":"` + n + `"}`))
+	return JWK{
+		Kty: "RSA",
+		Use: "sig",
+		Alg: "RS256",
+		Kid: base64.RawURLEncoding.EncodeToString(thumbprint[:]),
+		N:   n,
+		E:   e,
+	}
+}
+
+// issueAccessToken signs an RS256 access token for the user.
+func issueAccessToken(userID, username string, roles []string) (string, error) {
+	if roles == nil {
+		roles = []string{}
+	}
+	now := time.Now().UTC()
+	return signJWT(Claims{
+		Issuer:    tokenConfig.issuer,
+		Audience:  tokenConfig.audience,
+		Subject:   userID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(tokenConfig.accessTTL).Unix(),
+		ID:        generateID(),
+		Username:  username,
+		Roles:     roles,
+	})
+}
+
+func signJWT(claims Claims) (string, error) {
+	if tokenConfig.key == nil {
+		return "", errors.New("no signing key loaded")
+	}
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": tokenConfig.kid})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, tokenConfig.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// getJWKS publishes the public keys that access tokens are verified with.
+func getJWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(map[string][]JWK{"keys": tokenConfig.jwks})
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// usernameUniqueIndex makes usernames unique regardless of case. Deleted users keep
+// their username until they are purged, so they can always be restored.
+const usernameUniqueIndex = "users_username_lower_key"
+
+// Usernames are 3 to 30 characters: letters, digits, underscores and hyphens, starting
+// with a letter and ending with a letter or digit. They end up in profile URLs such as
+// /u/oskiegarcia, so nothing else is allowed.
+const (
+	minUsernameLength = 3
+	maxUsernameLength = 30
+)
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*[A-Za-z0-9]$`)
+
+// reservedUsernames can't be registered because they would impersonate the service or
+// collide with routes. They are compared in lower case.
+var reservedUsernames = map[string]bool{
+	"admin": true, "administrator": true, "root": true, "system": true, "moderator": true,
+	"staff": true, "support": true, "help": true, "official": true, "security": true,
+	"api": true, "graphql": true, "login": true, "logout": true, "register": true,
+	"signup": true, "signin": true, "settings": true, "account": true, "me": true,
+	"user": true, "users": true, "null": true, "undefined": true, "anonymous": true,
+}
+
+// Codes reported in UsernameError.
+const (
+	CodeInvalidUsername  = "INVALID_USERNAME"
+	CodeUsernameReserved = "USERNAME_RESERVED"
+	CodeUsernameTaken    = "USERNAME_TAKEN"
+)
+
+// UsernameError is the body of the 400 or 409 returned when a username can't be used,
+// so clients can tell the policy violations apart.
+type UsernameError struct {
+	Error    string `json:"error"`
+	Code     string `json:"code"`
+	Username string `json:"username"`
+}
+
+// checkUsername returns the policy violation of username, or nil if it may be used.
+func checkUsername(username string) *UsernameError {
+	invalid := func(msg string) *UsernameError {
+		return &UsernameError{Error: msg, Code: CodeInvalidUsername, Username: username}
+	}
+	switch {
+	case len(username) < minUsernameLength || len(username) > maxUsernameLength:
+		return invalid(fmt.Sprintf("username must be %d to %d characters", minUsernameLength, maxUsernameLength))
+	case !usernamePattern.MatchString(username):
+		return invalid("username may only contain letters, digits, underscores and hyphens, and must start with a letter and end with a letter or digit")
+	case reservedUsernames[strings.ToLower(username)]:
+		return &UsernameError{Error: "username is reserved", Code: CodeUsernameReserved, Username: username}
+	}
+	return nil
+}
+
+func writeUsernameError(w http.ResponseWriter, status int, usernameErr *UsernameError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(usernameErr)
+}
+
+// isUsernameConflict reports whether err was caused by the unique username index.
+func isUsernameConflict(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == usernameUniqueIndex
+}
+
+func writeUsernameConflict(w http.ResponseWriter, username string) {
+	writeUsernameError(w, http.StatusConflict, &UsernameError{Error: "username is taken", Code: CodeUsernameTaken, Username: username})
+}
+
+// migrateUniqueUsernames builds the case-insensitive username index. Usernames that
+// clashed before the index existed are resolved by keeping the oldest account's name,
+// ordered by ID since users have no creation time, and renaming the others with
+// dedupedUsername. The table is locked meanwhile so no one can take a new name.
+func migrateUniqueUsernames() error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("LOCK TABLE users IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query("SELECT id, username FROM users ORDER BY lower(username), id")
+	if err != nil {
+		return err
+	}
+	var duplicates []User
+	taken := map[string]bool{}
+	for rows.Next() {
+		var user User
+		if err := rows.Scan(&user.ID, &user.Username); err != nil {
+			rows.Close()
+			return err
+		}
+		if taken[strings.ToLower(user.Username)] {
+			duplicates = append(duplicates, user)
+		}
+		taken[strings.ToLower(user.Username)] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, user := range duplicates {
+		username := dedupedUsername(user.Username, user.ID, func(username string) bool {
+			return taken[strings.ToLower(username)]
+		})
+		if _, err := tx.Exec("UPDATE users SET username = $1 WHERE id = $2", username, user.ID); err != nil {
+			return err
+		}
+		taken[strings.ToLower(username)] = true
+		log.Printf("Renamed user %s from %s to %s to resolve a duplicate username\n", user.ID, user.Username, username)
+	}
+
+	if _, err := tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS " + usernameUniqueIndex + " ON users (lower(username))"); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// dedupedUsername returns a new username for the user with the given username and ID
+// that passes checkUsername and is not taken. It suffixes the username, shortened to fit,
+// with the start of the ID, using more of the ID and then a counter while the result is
+// taken. Usernames from before the policy that can't be kept this way become "user-"
+// with the same suffixes.
+func dedupedUsername(username, id string, taken func(string) bool) string {
+	try := func(suffix string) (string, bool) {
+		base := username
+		if n := maxUsernameLength - len(suffix) - 1; len(base) > n {
+			base = base[:max(n, 0)]
+		}
+		candidate := base + "-" + suffix
+		if checkUsername(candidate) != nil {
+			candidate = "user-" + suffix
+		}
+		return candidate, checkUsername(candidate) == nil && !taken(candidate)
+	}
+
+	for n := min(6, len(id)); n > 0 && n <= len(id); n++ {
+		if candidate, ok := try(id[:n]); ok {
+			return candidate
+		}
+	}
+	for i := 2; ; i++ {
+		if candidate, ok := try(strconv.Itoa(i)); ok {
+			return candidate
+		}
+	}
+}
+
+// getUserByUsername looks up a live user by username, ignoring case.
+func getUserByUsername(w http.ResponseWriter, r *http.Request) {
+	username := r.PathValue("username")
+
+	var user User
+	err := db.QueryRow("SELECT id, username FROM users WHERE lower(username) = lower($1) AND deleted_at IS NULL", username).
+		Scan(&user.ID, &user.Username)
+	if err == sql.ErrNoRows {
+		http.Error(w, "user not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("failed to query user: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDedupedUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		id       string
+		taken    []string
+		want     string
+	}{
+		{"suffixes the start of the ID", "oskie", "3fa9c1d2e4b5a607", nil, "oskie-3fa9c1"},
+		{"shortens long usernames to fit", strings.Repeat("a", 30), "3fa9c1d2e4b5a607", nil, strings.Repeat("a", 23) + "-3fa9c1"},
+		{"uses more of the ID when taken", "oskie", "3fa9c1d2e4b5a607", []string{"OSKIE-3fa9c1"}, "oskie-3fa9c1d"},
+		{"counts when the whole ID is taken", "oskie", "3fa9c1", []string{"oskie-3fa9c1"}, "oskie-2"},
+		{"replaces usernames that break the policy", "oskie garcia", "3fa9c1d2e4b5a607", nil, "user-3fa9c1"},
+		{"replaces usernames that start badly", "_oskie", "3fa9c1d2e4b5a607", nil, "user-3fa9c1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taken := func(username string) bool {
+				for _, name := range tt.taken {
+					if strings.EqualFold(name, username) {
+						return true
+					}
+				}
+				return false
+			}
+			got := dedupedUsername(tt.username, tt.id, taken)
+			if got != tt.want {
+				t.Errorf("dedupedUsername(%q, %q) = %q, want %q", tt.username, tt.id, got, tt.want)
+			}
+			if err := checkUsername(got); err != nil {
+				t.Errorf("dedupedUsername(%q, %q) = %q, which fails the policy: %s", tt.username, tt.id, got, err.Error)
+			}
+		})
+	}
+}
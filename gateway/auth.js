@@ -0,0 +1,101 @@
+const crypto = require('crypto');
+const { AuthenticationError } = require('apollo-server');
+
+// Access tokens are RS256 JWTs signed by the Users REST API, which publishes its public
+// keys as a JWKS document.
+const JWKS_URL = process.env.JWKS_URL || 'http://localhost:8080/.well-known/jwks.json';
+const JWT_ISSUER = process.env.JWT_ISSUER || 'http://localhost:8080';
+const JWT_AUDIENCE = process.env.JWT_AUDIENCE || 'product-reviews';
+
+// Tokens whose key isn't known yet trigger a refetch, at most this often, so rotated
+// keys are picked up without letting bogus key IDs hammer the Users API.
+const JWKS_REFETCH_INTERVAL_MS = 30 * 1000;
+
+let keys = new Map();
+let fetchedAt = 0;
+
+async function fetchKeys() {
+    fetchedAt = Date.now();
+    const res = await fetch(JWKS_URL);
+    if (!res.ok) {
+        throw new Error(`failed to fetch JWKS: ${res.status}`);
+    }
+    const { keys: jwks } = await res.json();
+    keys = new Map(
+        jwks.filter((jwk) => jwk.kty === 'RSA').map((jwk) => [jwk.kid, crypto.createPublicKey({ key: jwk, format: 'jwk' })])
+    );
+}
+
+async function publicKey(kid) {
+    if (!keys.has(kid) && Date.now() - fetchedAt > JWKS_REFETCH_INTERVAL_MS) {
+        await fetchKeys();
+    }
+    return keys.get(kid);
+}
+
+function decodeSegment(segment) {
+    return JSON.parse(Buffer.from(segment, 'base64url').toString('utf8'));
+}
+
+// verifyAccessToken checks the signature, issuer, audience and lifetime of a token and
+// returns its claims. Any problem throws an AuthenticationError.
+async function verifyAccessToken(token) {
+    const parts = token.split('.');
+    if (parts.length !== 3) {
+        throw new AuthenticationError('malformed access token');
+    }
+
+    let header, claims;
+    try {
+        header = decodeSegment(parts[0]);
+        claims = decodeSegment(parts[1]);
+    } catch {
+        throw new AuthenticationError('malformed access token');
+    }
+    if (header.alg !== 'RS256') {
+        throw new AuthenticationError('unsupported access token algorithm');
+    }
+
+    const key = await publicKey(header.kid);
+    if (!key) {
+        throw new AuthenticationError('access token was signed with an unknown key');
+    }
+    const signature = Buffer.from(parts[2], 'base64url');
+    if (!crypto.verify('RSA-SHA256', Buffer.from(`${parts[0]}.${parts[1]}`), key, signature)) {
+        throw new AuthenticationError('invalid access token signature');
+    }
+
+    const now = Math.floor(Date.now() / 1000);
+    if (claims.iss !== JWT_ISSUER || claims.aud !== JWT_AUDIENCE) {
+        throw new AuthenticationError('access token was not issued for this gateway');
+    }
+    if (typeof claims.exp !== 'number' || claims.exp <= now) {
+        throw new AuthenticationError('access token has expired');
+    }
+    if (!claims.sub) {
+        throw new AuthenticationError('access token has no subject');
+    }
+    return claims;
+}
+
+// TRUST_IDENTITY_HEADERS lets local tools impersonate users by sending X-User-Id and
+// X-User-Roles directly. It must never be enabled where the gateway is reachable by
+// clients.
+const TRUST_IDENTITY_HEADERS = process.env.TRUST_IDENTITY_HEADERS === 'true';
+
+// callerFromRequest returns the identity to forward to subgraphs: the subject and roles
+// of a valid bearer token, or nothing for anonymous requests.
+async function callerFromRequest(req) {
+    const authorization = req.headers.authorization || '';
+    const match = authorization.match(/^Bearer\s+(\S+)$/i);
+    if (match) {
+        const claims = await verifyAccessToken(match[1]);
+        return { userId: claims.sub, userRoles: (claims.roles || []).join(',') };
+    }
+    if (TRUST_IDENTITY_HEADERS) {
+        return { userId: req.headers['x-user-id'], userRoles: req.headers['x-user-roles'] };
+    }
+    return {};
+}
+
+module.exports = { callerFromRequest };
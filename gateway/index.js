@@ -1,8 +1,23 @@
 const { ApolloServer } = require('apollo-server');
 const { ApolloGateway, IntrospectAndCompose, RemoteGraphQLDataSource } = require('@apollo/gateway');
+const { callerFromRequest } = require('./auth');
 
 class MonitoredDataSource extends RemoteGraphQLDataSource {
     willSendRequest({ request, context }) {
+        // Pass the caller identity on so subgraphs can attribute writes such as votes
+        // and restrict moderation to admins. The identity comes from the verified
+        // access token, see auth.js.
+        if (context.userId) {
+            request.http.headers.set('x-user-id', context.userId);
+        }
+        if (context.userRoles) {
+            request.http.headers.set('x-user-roles', context.userRoles);
+        }
+        // Subgraphs only trust identity headers that come with GATEWAY_SECRET, and
+        // treat every request as anonymous when it is not set.
+        if (process.env.GATEWAY_SECRET) {
+            request.http.headers.set('x-gateway-secret', process.env.GATEWAY_SECRET);
+        }
         console.log('------------');
         console.log(`[Apollo Gateway] Routing request to subgraph: ${this.url}`);
         // You can also log the query itself:
@@ -15,7 +30,8 @@ const gateway = new ApolloGateway({
         subgraphs: [
             { name: 'products', url: 'http://localhost:4001/query' },
             { name: 'users', url: 'http://localhost:4002/query' },
-            { name: 'reviews', url: 'http://localhost:4003/query' }
+            { name: 'reviews', url: 'http://localhost:4003/query' },
+            { name: 'orders', url: 'http://localhost:4004/query' }
         ],
     }),
     buildService({ name, url }) {
@@ -26,6 +42,7 @@ const gateway = new ApolloGateway({
 const server = new ApolloServer({
     gateway,
     subscriptions: false,
+    context: ({ req }) => callerFromRequest(req),
 });
 
 server.listen({ port: 4000 }).then(({ url }) => {
@@ -0,0 +1,26 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package generated
+
+import (
+	"orders/internal/order/models"
+)
+
+type Product struct {
+	ID string `json:"id"`
+	// Number of paid, shipped or delivered orders that include the product.
+	PurchaseCount int `json:"purchaseCount"`
+}
+
+func (Product) IsEntity() {}
+
+type Query struct {
+}
+
+type User struct {
+	ID string `json:"id"`
+	// The user's orders, newest first. Only the user themselves and admins can see them.
+	Orders []*models.Order `json:"orders"`
+}
+
+func (User) IsEntity() {}
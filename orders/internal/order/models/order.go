@@ -0,0 +1,70 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// OrderStatus maps to the OrderStatus GraphQL enum
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "PENDING"
+	OrderStatusPaid      OrderStatus = "PAID"
+	OrderStatusShipped   OrderStatus = "SHIPPED"
+	OrderStatusDelivered OrderStatus = "DELIVERED"
+	OrderStatusCancelled OrderStatus = "CANCELLED"
+)
+
+func (s OrderStatus) IsValid() bool {
+	switch s {
+	case OrderStatusPending, OrderStatusPaid, OrderStatusShipped, OrderStatusDelivered, OrderStatusCancelled:
+		return true
+	}
+	return false
+}
+
+func (s *OrderStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*s = OrderStatus(str)
+	if !s.IsValid() {
+		return fmt.Errorf("%s is not a valid OrderStatus", str)
+	}
+	return nil
+}
+
+func (s OrderStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(string(s)))
+}
+
+type Order struct {
+	ID        string       `json:"id"`
+	UserID    string       `json:"userId"`
+	Status    OrderStatus  `json:"status"`
+	CreatedAt string       `json:"createdAt"`
+	Lines     []*OrderLine `json:"lines"`
+	Total     int          `json:"total"`
+}
+
+type OrderLine struct {
+	ProductID string `json:"productId"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice int    `json:"unitPrice"`
+}
+
+// UserOrders holds the orders the orders API returned for a user
+type UserOrders struct {
+	UserID string   `json:"userId"`
+	Orders []*Order `json:"orders"`
+}
+
+// PurchaseCount holds the number of purchases the orders API counted for a product
+type PurchaseCount struct {
+	ProductID     string `json:"productId"`
+	PurchaseCount int    `json:"purchaseCount"`
+}
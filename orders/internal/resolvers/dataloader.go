@@ -0,0 +1,140 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"orders/internal/order/models"
+
+	"github.com/vikstrous/dataloadgen"
+)
+
+type CtxKey string
+
+const (
+	UserOrdersKey     CtxKey = "userOrdersLoader"
+	PurchaseCountsKey CtxKey = "purchaseCountsLoader"
+	ApiCounterKey     CtxKey = "apiCounterLoader"
+)
+
+type ApiCounter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (c *ApiCounter) Increment(endpoint string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = make(map[string]int)
+	}
+	c.counts[endpoint]++
+}
+
+func GetApiCounter(ctx context.Context) *ApiCounter {
+	if counter, ok := ctx.Value(ApiCounterKey).(*ApiCounter); ok {
+		return counter
+	}
+	return nil
+}
+
+// FetchUserOrders batches order lookups for users
+func FetchUserOrders(ctx context.Context, userIds []string) ([][]*models.Order, []error) {
+	url := "http://localhost:8083/orders?userIds=" + strings.Join(userIds, ",")
+	fmt.Printf("[Orders Subgraph] Making REST call to: %s\n", url)
+	GetApiCounter(ctx).Increment("/orders")
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to fetch orders: %v", err)}
+	}
+	defer resp.Body.Close()
+
+	var apiOrders []models.UserOrders
+	if err := json.NewDecoder(resp.Body).Decode(&apiOrders); err != nil {
+		return nil, []error{fmt.Errorf("failed to decode orders: %v", err)}
+	}
+
+	orderMap := make(map[string][]*models.Order)
+	for _, u := range apiOrders {
+		orderMap[u.UserID] = u.Orders
+	}
+
+	results := make([][]*models.Order, len(userIds))
+	errors := make([]error, len(userIds))
+
+	for i, id := range userIds {
+		orders := orderMap[id]
+		if orders == nil {
+			orders = []*models.Order{}
+		}
+		results[i] = orders
+	}
+
+	return results, errors
+}
+
+// FetchPurchaseCounts batches purchase count lookups for products
+func FetchPurchaseCounts(ctx context.Context, productIds []string) ([]int, []error) {
+	url := "http://localhost:8083/orders/purchase-counts?productIds=" + strings.Join(productIds, ",")
+	fmt.Printf("[Orders Subgraph] Making REST call to: %s\n", url)
+	GetApiCounter(ctx).Increment("/orders/purchase-counts")
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to fetch purchase counts: %v", err)}
+	}
+	defer resp.Body.Close()
+
+	var apiCounts []models.PurchaseCount
+	if err := json.NewDecoder(resp.Body).Decode(&apiCounts); err != nil {
+		return nil, []error{fmt.Errorf("failed to decode purchase counts: %v", err)}
+	}
+
+	countMap := make(map[string]int)
+	for _, c := range apiCounts {
+		countMap[c.ProductID] = c.PurchaseCount
+	}
+
+	results := make([]int, len(productIds))
+	errors := make([]error, len(productIds))
+
+	for i, id := range productIds {
+		results[i] = countMap[id]
+	}
+
+	return results, errors
+}
+
+func DataLoaderMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counter := &ApiCounter{counts: make(map[string]int)}
+		ctx := context.WithValue(r.Context(), ApiCounterKey, counter)
+
+		userOrdersLoader := dataloadgen.NewLoader(FetchUserOrders)
+		purchaseCountsLoader := dataloadgen.NewLoader(FetchPurchaseCounts)
+
+		ctx = context.WithValue(ctx, UserOrdersKey, userOrdersLoader)
+		ctx = context.WithValue(ctx, PurchaseCountsKey, purchaseCountsLoader)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+
+		for endpoint, count := range counter.counts {
+			fmt.Printf("[Orders Subgraph] GraphQL query completed: %s, count=%d\n", endpoint, count)
+		}
+	})
+}
+
+// Helpers
+func CtxUserOrdersProvider(ctx context.Context) *dataloadgen.Loader[string, []*models.Order] {
+	return ctx.Value(UserOrdersKey).(*dataloadgen.Loader[string, []*models.Order])
+}
+
+func CtxPurchaseCountProvider(ctx context.Context) *dataloadgen.Loader[string, int] {
+	return ctx.Value(PurchaseCountsKey).(*dataloadgen.Loader[string, int])
+}
@@ -0,0 +1,29 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.87
+
+import (
+	"context"
+	"orders/internal/generated"
+)
+
+// FindProductByID is the resolver for the findProductByID field.
+func (r *entityResolver) FindProductByID(ctx context.Context, id string) (*generated.Product, error) {
+	// Purchase counts are loaded by the Product field resolvers.
+	return &generated.Product{ID: id}, nil
+}
+
+// FindUserByID is the resolver for the findUserByID field.
+func (r *entityResolver) FindUserByID(ctx context.Context, id string) (*generated.User, error) {
+	// Orders are private, so they are loaded by the User field resolver once the
+	// caller has been checked.
+	return &generated.User{ID: id}, nil
+}
+
+// Entity returns generated.EntityResolver implementation.
+func (r *Resolver) Entity() generated.EntityResolver { return &entityResolver{r} }
+
+type entityResolver struct{ *Resolver }
@@ -0,0 +1,52 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.87
+
+import (
+	"context"
+	"orders/internal/generated"
+	"orders/internal/order/models"
+)
+
+// Buyer is the resolver for the buyer field.
+func (r *orderResolver) Buyer(ctx context.Context, obj *models.Order) (*generated.User, error) {
+	return &generated.User{ID: obj.UserID}, nil
+}
+
+// Product is the resolver for the product field.
+func (r *orderLineResolver) Product(ctx context.Context, obj *models.OrderLine) (*generated.Product, error) {
+	return &generated.Product{ID: obj.ProductID}, nil
+}
+
+// PurchaseCount is the resolver for the purchaseCount field.
+func (r *productResolver) PurchaseCount(ctx context.Context, obj *generated.Product) (int, error) {
+	return CtxPurchaseCountProvider(ctx).Load(ctx, obj.ID)
+}
+
+// Orders is the resolver for the orders field.
+func (r *userResolver) Orders(ctx context.Context, obj *generated.User) ([]*models.Order, error) {
+	if _, err := CtxOrdersOf(ctx, obj.ID); err != nil {
+		return nil, err
+	}
+	return CtxUserOrdersProvider(ctx).Load(ctx, obj.ID)
+}
+
+// Order returns generated.OrderResolver implementation.
+func (r *Resolver) Order() generated.OrderResolver { return &orderResolver{r} }
+
+// OrderLine returns generated.OrderLineResolver implementation.
+func (r *Resolver) OrderLine() generated.OrderLineResolver { return &orderLineResolver{r} }
+
+// Product returns generated.ProductResolver implementation.
+func (r *Resolver) Product() generated.ProductResolver { return &productResolver{r} }
+
+// User returns generated.UserResolver implementation.
+func (r *Resolver) User() generated.UserResolver { return &userResolver{r} }
+
+type orderResolver struct{ *Resolver }
+type orderLineResolver struct{ *Resolver }
+type productResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
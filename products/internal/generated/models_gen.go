@@ -2,5 +2,95 @@
 
 package generated
 
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type CreateProductInput struct {
+	Name string `json:"name"`
+	// Must not be negative.
+	Price int `json:"price"`
+}
+
+// Catalog changes. They require the merchant or admin role. Invalid input fails with a
+// VALIDATION_FAILED error naming the offending field, and unknown products with a
+// PRODUCT_NOT_FOUND error, both as the code in the error extensions.
+type Mutation struct {
+}
+
 type Query struct {
 }
+
+// Fields left out keep their current value.
+type UpdateProductInput struct {
+	Name *string `json:"name,omitempty"`
+	// Must not be negative.
+	Price *int `json:"price,omitempty"`
+}
+
+// How topProducts orders the catalog.
+type ProductRanking string
+
+const (
+	// Best reviewed first, by a Bayesian average of approved review ratings. Products without reviews come last.
+	ProductRankingRating ProductRanking = "RATING"
+	// Most approved reviews first. Products without reviews come last.
+	ProductRankingReviewCount ProductRanking = "REVIEW_COUNT"
+	// Most recently added first.
+	ProductRankingNewest ProductRanking = "NEWEST"
+	// Cheapest first.
+	ProductRankingPrice ProductRanking = "PRICE"
+)
+
+var AllProductRanking = []ProductRanking{
+	ProductRankingRating,
+	ProductRankingReviewCount,
+	ProductRankingNewest,
+	ProductRankingPrice,
+}
+
+func (e ProductRanking) IsValid() bool {
+	switch e {
+	case ProductRankingRating, ProductRankingReviewCount, ProductRankingNewest, ProductRankingPrice:
+		return true
+	}
+	return false
+}
+
+func (e ProductRanking) String() string {
+	return string(e)
+}
+
+func (e *ProductRanking) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProductRanking(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProductRanking", str)
+	}
+	return nil
+}
+
+func (e ProductRanking) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ProductRanking) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ProductRanking) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,74 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"products/internal/product/models"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Error codes reported in the extensions of product mutation errors.
+const (
+	CodeProductNotFound  = "PRODUCT_NOT_FOUND"
+	CodeValidationFailed = "VALIDATION_FAILED"
+)
+
+func productNotFoundError(id string) error {
+	return &gqlerror.Error{
+		Message:    "product not found",
+		Extensions: map[string]any{"code": CodeProductNotFound, "productId": id},
+	}
+}
+
+// validationError reports an invalid input field. field is empty when the products API
+// rejected the input without saying which field was wrong.
+func validationError(field, message string) error {
+	extensions := map[string]any{"code": CodeValidationFailed}
+	if field != "" {
+		extensions["field"] = field
+	}
+	return &gqlerror.Error{Message: message, Extensions: extensions}
+}
+
+// validateProduct checks a product before it is written, trimming its name.
+func validateProduct(product *models.Product) error {
+	product.Name = strings.TrimSpace(product.Name)
+	if product.Name == "" {
+		return validationError("name", "name must not be empty")
+	}
+	if product.Price < 0 {
+		return validationError("price", "price must not be negative")
+	}
+	return nil
+}
+
+// productAPIError turns the products API's not-found and bad-request responses for
+// product id into typed errors.
+func productAPIError(err error, id string) error {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	switch apiErr.status {
+	case http.StatusNotFound:
+		return productNotFoundError(id)
+	case http.StatusBadRequest:
+		return validationError("", apiErr.msg)
+	}
+	return err
+}
+
+// loadProduct reads a product straight from the products API, bypassing the dataloader
+// so that mutations see its current state.
+func loadProduct(ctx context.Context, id string) (*models.Product, error) {
+	var product models.Product
+	if err := callProductsAPI(ctx, http.MethodGet, "/products/"+url.PathEscape(id), nil, &product); err != nil {
+		return nil, productAPIError(err, id)
+	}
+	return &product, nil
+}
@@ -0,0 +1,129 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"products/internal/generated"
+	"products/internal/product/models"
+)
+
+// productOrders maps the catalog rankings to the orderBy parameter of the products API.
+var productOrders = map[generated.ProductRanking]string{
+	generated.ProductRankingNewest: "newest",
+	generated.ProductRankingPrice:  "price",
+}
+
+// reviewRankings maps the review-based rankings to the rankBy parameter of the reviews
+// API, which ranks products from its precomputed rating stats.
+var reviewRankings = map[generated.ProductRanking]string{
+	generated.ProductRankingRating:      "rating",
+	generated.ProductRankingReviewCount: "review_count",
+}
+
+// rankingPageSize is the most ranked products the reviews API returns per request; it
+// caps limit at 1000, so longer rankings are read a page at a time.
+const rankingPageSize = 1000
+
+// rankedProductID is the part of a reviews API ranking entry the products subgraph uses.
+type rankedProductID struct {
+	ProductID string `json:"productId"`
+}
+
+// fetchJSON GETs url and decodes the JSON response into out.
+func fetchJSON(ctx context.Context, url, endpoint string, out any) error {
+	fmt.Printf("[Products Subgraph] Making REST call to: %s\n", url)
+	GetApiCounter(ctx).Increment(endpoint)
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s", strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// fetchProductPage returns up to limit products sorted by the products API.
+func fetchProductPage(ctx context.Context, orderBy string, limit int) ([]*models.Product, error) {
+	var products []*models.Product
+	url := fmt.Sprintf("http://localhost:8081/products?orderBy=%s&limit=%d", orderBy, limit)
+	if err := fetchJSON(ctx, url, "/products", &products); err != nil {
+		return nil, fmt.Errorf("failed to fetch products: %v", err)
+	}
+	return products, nil
+}
+
+// rankProducts returns the first products of the catalog in the given order. Both
+// services sort and limit in SQL, so only the requested page is ever transferred.
+func rankProducts(ctx context.Context, first int, rankBy generated.ProductRanking) ([]*models.Product, error) {
+	if first < 1 {
+		return []*models.Product{}, nil
+	}
+	if orderBy, ok := productOrders[rankBy]; ok {
+		return fetchProductPage(ctx, orderBy, first)
+	}
+	rankedBy, ok := reviewRankings[rankBy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported ranking %s", rankBy)
+	}
+
+	// Ranked products that have since been deleted are skipped, and the reviews API
+	// caps its pages, so further pages are read until the list is full or a short page
+	// shows the ranking has run out.
+	pageSize := min(first, rankingPageSize)
+	var products []*models.Product
+	seen := make(map[string]bool)
+	for offset := 0; ; offset += pageSize {
+		var ranked []rankedProductID
+		url := fmt.Sprintf("http://localhost:8082/reviews/top-products?rankBy=%s&limit=%d&offset=%d", rankedBy, pageSize, offset)
+		if err := fetchJSON(ctx, url, "/reviews/top-products", &ranked); err != nil {
+			return nil, fmt.Errorf("failed to rank products: %v", err)
+		}
+
+		ids := make([]string, len(ranked))
+		for i, r := range ranked {
+			ids[i] = r.ProductID
+		}
+		found, err := CtxLoadProvider(ctx).LoadAll(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range found {
+			if p != nil && len(products) < first {
+				products = append(products, p)
+				seen[p.ID] = true
+			}
+		}
+
+		if len(products) == first {
+			return products, nil
+		}
+		if len(ranked) < pageSize {
+			break
+		}
+	}
+
+	// Every reviewed product is already in the list, so the page is filled with the
+	// newest products that have no reviews yet.
+	newest, err := fetchProductPage(ctx, productOrders[generated.ProductRankingNewest], first+len(products))
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range newest {
+		if len(products) == first {
+			break
+		}
+		if !seen[p.ID] {
+			products = append(products, p)
+		}
+	}
+	return products, nil
+}
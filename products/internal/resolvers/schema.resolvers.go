@@ -7,35 +7,93 @@ package resolvers
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
+	"net/url"
 	"products/internal/generated"
 	"products/internal/product/models"
 )
 
-// TopProducts is the resolver for the topProducts field.
-func (r *queryResolver) TopProducts(ctx context.Context, first *int) ([]*models.Product, error) {
-	resp, err := http.Get("http://localhost:8081/products")
+// CreateProduct is the resolver for the createProduct field.
+func (r *mutationResolver) CreateProduct(ctx context.Context, input generated.CreateProductInput) (*models.Product, error) {
+	if _, err := CtxMerchant(ctx); err != nil {
+		return nil, err
+	}
+
+	product := models.Product{Name: input.Name, Price: input.Price}
+	if err := validateProduct(&product); err != nil {
+		return nil, err
+	}
+
+	var created models.Product
+	if err := callProductsAPI(ctx, http.MethodPost, "/products", product, &created); err != nil {
+		return nil, productAPIError(err, "")
+	}
+	return &created, nil
+}
+
+// UpdateProduct is the resolver for the updateProduct field.
+func (r *mutationResolver) UpdateProduct(ctx context.Context, id string, input generated.UpdateProductInput) (*models.Product, error) {
+	if _, err := CtxMerchant(ctx); err != nil {
+		return nil, err
+	}
+
+	// The products API replaces the whole product, so fields left out of the input are
+	// filled in from the current version.
+	product, err := loadProduct(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch products: %v", err)
+		return nil, err
+	}
+	if input.Name != nil {
+		product.Name = *input.Name
+	}
+	if input.Price != nil {
+		product.Price = *input.Price
+	}
+	if err := validateProduct(product); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var products []*models.Product
-	if err := json.NewDecoder(resp.Body).Decode(&products); err != nil {
-		return nil, fmt.Errorf("failed to decode products: %v", err)
+	var updated models.Product
+	if err := callProductsAPI(ctx, http.MethodPut, "/products/"+url.PathEscape(id), product, &updated); err != nil {
+		return nil, productAPIError(err, id)
 	}
+	return &updated, nil
+}
 
-	limit := len(products)
-	if first != nil && *first < limit {
-		limit = *first
+// DeleteProduct is the resolver for the deleteProduct field.
+func (r *mutationResolver) DeleteProduct(ctx context.Context, id string) (*models.Product, error) {
+	if _, err := CtxMerchant(ctx); err != nil {
+		return nil, err
+	}
+
+	// Load the product first so the deleted entity can be handed back to the client.
+	deleted, err := loadProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := callProductsAPI(ctx, http.MethodDelete, "/products/"+url.PathEscape(id), nil, nil); err != nil {
+		return nil, productAPIError(err, id)
 	}
+	return deleted, nil
+}
 
-	return products[:limit], nil
+// TopProducts is the resolver for the topProducts field.
+func (r *queryResolver) TopProducts(ctx context.Context, first *int, rankBy *generated.ProductRanking) ([]*models.Product, error) {
+	limit, ranking := 5, generated.ProductRankingRating
+	if first != nil {
+		limit = *first
+	}
+	if rankBy != nil {
+		ranking = *rankBy
+	}
+	return rankProducts(ctx, limit, ranking)
 }
 
+// Mutation returns generated.MutationResolver implementation.
+func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
+
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
@@ -24,8 +24,11 @@ func main() {
 
 	http.Handle("/", playground.Handler("GraphQL playground", "/query"))
 	// Wrap /query with the middleware to inject dataloader
-	http.Handle("/query", resolvers.DataLoaderMiddleware(srv))
+	http.Handle("/query", resolvers.ViewerMiddleware(resolvers.DataLoaderMiddleware(srv)))
 
+	if os.Getenv("GATEWAY_SECRET") == "" {
+		log.Printf("GATEWAY_SECRET is not set: identity headers are ignored and every request is anonymous")
+	}
 	log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
 	log.Fatal(http.ListenAndServe(":"+port, nil))
 }
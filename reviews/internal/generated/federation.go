@@ -153,6 +153,25 @@ func (ec *executionContext) resolveEntity(
 	}()
 
 	switch typeName {
+	case "Answer":
+		resolverName, err := entityResolverNameForAnswer(ctx, rep)
+		if err != nil {
+			return nil, fmt.Errorf(`finding resolver for Entity "Answer": %w`, err)
+		}
+		switch resolverName {
+
+		case "findAnswerByID":
+			id0, err := ec.unmarshalNID2string(ctx, rep["id"])
+			if err != nil {
+				return nil, fmt.Errorf(`unmarshalling param 0 for findAnswerByID(): %w`, err)
+			}
+			entity, err := ec.Resolvers.Entity().FindAnswerByID(ctx, id0)
+			if err != nil {
+				return nil, fmt.Errorf(`resolving Entity "Answer": %w`, err)
+			}
+
+			return entity, nil
+		}
 	case "Product":
 		resolverName, err := entityResolverNameForProduct(ctx, rep)
 		if err != nil {
@@ -170,6 +189,25 @@ func (ec *executionContext) resolveEntity(
 				return nil, fmt.Errorf(`resolving Entity "Product": %w`, err)
 			}
 
+			return entity, nil
+		}
+	case "Question":
+		resolverName, err := entityResolverNameForQuestion(ctx, rep)
+		if err != nil {
+			return nil, fmt.Errorf(`finding resolver for Entity "Question": %w`, err)
+		}
+		switch resolverName {
+
+		case "findQuestionByID":
+			id0, err := ec.unmarshalNID2string(ctx, rep["id"])
+			if err != nil {
+				return nil, fmt.Errorf(`unmarshalling param 0 for findQuestionByID(): %w`, err)
+			}
+			entity, err := ec.Resolvers.Entity().FindQuestionByID(ctx, id0)
+			if err != nil {
+				return nil, fmt.Errorf(`resolving Entity "Question": %w`, err)
+			}
+
 			return entity, nil
 		}
 	case "Review":
@@ -236,6 +274,41 @@ func (ec *executionContext) resolveManyEntities(
 	}
 }
 
+func entityResolverNameForAnswer(ctx context.Context, rep EntityRepresentation) (string, error) {
+	// we collect errors because a later entity resolver may work fine
+	// when an entity has multiple keys
+	entityResolverErrs := []error{}
+	for {
+		var (
+			m   EntityRepresentation
+			val any
+			ok  bool
+		)
+		_ = val
+		// if all of the KeyFields values for this resolver are null,
+		// we shouldn't use use it
+		allNull := true
+		m = rep
+		val, ok = m["id"]
+		if !ok {
+			entityResolverErrs = append(entityResolverErrs,
+				fmt.Errorf("%w due to missing Key Field \"id\" for Answer", ErrTypeNotFound))
+			break
+		}
+		if allNull {
+			allNull = val == nil
+		}
+		if allNull {
+			entityResolverErrs = append(entityResolverErrs,
+				fmt.Errorf("%w due to all null value KeyFields for Answer", ErrTypeNotFound))
+			break
+		}
+		return "findAnswerByID", nil
+	}
+	return "", fmt.Errorf("%w for Answer due to %v", ErrTypeNotFound,
+		errors.Join(entityResolverErrs...).Error())
+}
+
 func entityResolverNameForProduct(ctx context.Context, rep EntityRepresentation) (string, error) {
 	// we collect errors because a later entity resolver may work fine
 	// when an entity has multiple keys
@@ -271,6 +344,41 @@ func entityResolverNameForProduct(ctx context.Context, rep EntityRepresentation)
 		errors.Join(entityResolverErrs...).Error())
 }
 
+func entityResolverNameForQuestion(ctx context.Context, rep EntityRepresentation) (string, error) {
+	// we collect errors because a later entity resolver may work fine
+	// when an entity has multiple keys
+	entityResolverErrs := []error{}
+	for {
+		var (
+			m   EntityRepresentation
+			val any
+			ok  bool
+		)
+		_ = val
+		// if all of the KeyFields values for this resolver are null,
+		// we shouldn't use use it
+		allNull := true
+		m = rep
+		val, ok = m["id"]
+		if !ok {
+			entityResolverErrs = append(entityResolverErrs,
+				fmt.Errorf("%w due to missing Key Field \"id\" for Question", ErrTypeNotFound))
+			break
+		}
+		if allNull {
+			allNull = val == nil
+		}
+		if allNull {
+			entityResolverErrs = append(entityResolverErrs,
+				fmt.Errorf("%w due to all null value KeyFields for Question", ErrTypeNotFound))
+			break
+		}
+		return "findQuestionByID", nil
+	}
+	return "", fmt.Errorf("%w for Question due to %v", ErrTypeNotFound,
+		errors.Join(entityResolverErrs...).Error())
+}
+
 func entityResolverNameForReview(ctx context.Context, rep EntityRepresentation) (string, error) {
 	// we collect errors because a later entity resolver may work fine
 	// when an entity has multiple keys
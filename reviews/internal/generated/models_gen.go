@@ -3,12 +3,43 @@
 package generated
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"product-reviews/internal/review/models"
+	"strconv"
 )
 
+type CreateReviewInput struct {
+	ProductID string `json:"productId"`
+	Body      string `json:"body"`
+	Rating    int    `json:"rating"`
+}
+
+type Mutation struct {
+}
+
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor,omitempty"`
+	EndCursor       *string `json:"endCursor,omitempty"`
+}
+
 type Product struct {
-	ID      string           `json:"id"`
-	Reviews []*models.Review `json:"reviews,omitempty"`
+	ID                 string                     `json:"id"`
+	Reviews            []*models.Review           `json:"reviews,omitempty"`
+	ReviewsConnection  *ReviewConnection          `json:"reviewsConnection"`
+	AverageRating      *float64                   `json:"averageRating,omitempty"`
+	ReviewCount        int                        `json:"reviewCount"`
+	RatingHistogram    []*models.RatingBucket     `json:"ratingHistogram"`
+	SentimentBreakdown *models.SentimentBreakdown `json:"sentimentBreakdown"`
+	// What reviewers talk about, most mentioned first.
+	ReviewAspects []*models.ReviewAspect `json:"reviewAspects"`
+	// Null until the product's reviews have been summarised.
+	ReviewSummary *models.ReviewSummary `json:"reviewSummary,omitempty"`
+	// Questions shoppers asked about the product, newest first.
+	Questions *QuestionConnection `json:"questions"`
 }
 
 func (Product) IsEntity() {}
@@ -16,10 +47,130 @@ func (Product) IsEntity() {}
 type Query struct {
 }
 
+type QuestionConnection struct {
+	Edges    []*QuestionEdge `json:"edges"`
+	PageInfo *PageInfo       `json:"pageInfo"`
+}
+
+type QuestionEdge struct {
+	Cursor string           `json:"cursor"`
+	Node   *models.Question `json:"node"`
+}
+
+type ReviewConnection struct {
+	Edges    []*ReviewEdge `json:"edges"`
+	PageInfo *PageInfo     `json:"pageInfo"`
+}
+
+type ReviewEdge struct {
+	Cursor string         `json:"cursor"`
+	Node   *models.Review `json:"node"`
+}
+
+type ReviewFilter struct {
+	MinRating      *int                   `json:"minRating,omitempty"`
+	MaxRating      *int                   `json:"maxRating,omitempty"`
+	CreatedAfter   *string                `json:"createdAfter,omitempty"`
+	CreatedBefore  *string                `json:"createdBefore,omitempty"`
+	HasBody        *bool                  `json:"hasBody,omitempty"`
+	Sentiment      *models.SentimentLabel `json:"sentiment,omitempty"`
+	RatingMismatch *bool                  `json:"ratingMismatch,omitempty"`
+	// Only reviews that mention this aspect, as named in Product.reviewAspects.
+	Aspect *string `json:"aspect,omitempty"`
+}
+
+type ReviewSearchConnection struct {
+	Edges    []*ReviewSearchEdge `json:"edges"`
+	PageInfo *PageInfo           `json:"pageInfo"`
+}
+
+// A review matching a full-text search.
+type ReviewSearchEdge struct {
+	Cursor string         `json:"cursor"`
+	Node   *models.Review `json:"node"`
+	// How well the review matches the query; higher is more relevant.
+	Rank float64 `json:"rank"`
+	// HTML-escaped excerpts of the body with matched terms wrapped in <mark> tags.
+	Snippet string `json:"snippet"`
+}
+
+type UpdateReviewInput struct {
+	Body   *string `json:"body,omitempty"`
+	Rating *int    `json:"rating,omitempty"`
+}
+
+type UpsertMyReviewInput struct {
+	ProductID string `json:"productId"`
+	Body      string `json:"body"`
+	Rating    int    `json:"rating"`
+}
+
 type User struct {
-	ID           string           `json:"id"`
-	TotalReviews *int             `json:"totalReviews,omitempty"`
-	Reviews      []*models.Review `json:"reviews,omitempty"`
+	ID                string            `json:"id"`
+	TotalReviews      *int              `json:"totalReviews,omitempty"`
+	Reviews           []*models.Review  `json:"reviews,omitempty"`
+	ReviewsConnection *ReviewConnection `json:"reviewsConnection"`
 }
 
 func (User) IsEntity() {}
+
+type ReviewOrder string
+
+const (
+	ReviewOrderNewest      ReviewOrder = "NEWEST"
+	ReviewOrderOldest      ReviewOrder = "OLDEST"
+	ReviewOrderHighest     ReviewOrder = "HIGHEST"
+	ReviewOrderLowest      ReviewOrder = "LOWEST"
+	ReviewOrderMostHelpful ReviewOrder = "MOST_HELPFUL"
+)
+
+var AllReviewOrder = []ReviewOrder{
+	ReviewOrderNewest,
+	ReviewOrderOldest,
+	ReviewOrderHighest,
+	ReviewOrderLowest,
+	ReviewOrderMostHelpful,
+}
+
+func (e ReviewOrder) IsValid() bool {
+	switch e {
+	case ReviewOrderNewest, ReviewOrderOldest, ReviewOrderHighest, ReviewOrderLowest, ReviewOrderMostHelpful:
+		return true
+	}
+	return false
+}
+
+func (e ReviewOrder) String() string {
+	return string(e)
+}
+
+func (e *ReviewOrder) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReviewOrder(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReviewOrder", str)
+	}
+	return nil
+}
+
+func (e ReviewOrder) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ReviewOrder) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ReviewOrder) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
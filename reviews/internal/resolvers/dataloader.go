@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
@@ -17,10 +18,22 @@ import (
 type CtxKey string
 
 const (
-	ProductReviewsKey CtxKey = "productReviewsLoader"
-	UserReviewsKey    CtxKey = "userReviewsLoader"
-	ReviewKey         CtxKey = "reviewLoader"
-	ApiCounterKey     CtxKey = "apiCounterLoader"
+	ProductReviewsKey  CtxKey = "productReviewsLoader"
+	UserReviewsKey     CtxKey = "userReviewsLoader"
+	UserReviewListKey  CtxKey = "userReviewListLoader"
+	ReviewKey          CtxKey = "reviewLoader"
+	ProductStatsKey    CtxKey = "productStatsLoader"
+	ReviewVotesKey     CtxKey = "reviewVotesLoader"
+	ResponsesKey       CtxKey = "reviewResponsesLoader"
+	RevisionsKey       CtxKey = "reviewRevisionsLoader"
+	ProductAspectsKey  CtxKey = "productAspectsLoader"
+	DuplicatesKey      CtxKey = "reviewDuplicatesLoader"
+	SummariesKey       CtxKey = "reviewSummariesLoader"
+	PurchasesKey       CtxKey = "verifiedPurchasesLoader"
+	QuestionKey        CtxKey = "questionLoader"
+	AnswerKey          CtxKey = "answerLoader"
+	QuestionAnswersKey CtxKey = "questionAnswersLoader"
+	ApiCounterKey      CtxKey = "apiCounterLoader"
 )
 
 type ApiCounter struct {
@@ -82,49 +95,227 @@ func FetchReviews(ctx context.Context, ids []string) ([]*models.Review, []error)
 	return results, errors
 }
 
-// FetchProductReviews dynamically groups array returns associated to specific products
-func FetchProductReviews(ctx context.Context, productIds []string) ([]*generated.Product, []error) {
-	url := "http://localhost:8082/reviews?productIds=" + strings.Join(productIds, ",")
+// ReviewListQuery identifies one filtered, ordered review list belonging to a product or
+// a user. Limit 0 means no limit, and Filter holds the encoded filter query parameters.
+type ReviewListQuery struct {
+	OwnerID string
+	Limit   int
+	Order   string
+	Filter  string
+}
+
+// FetchProductReviews loads review lists for many products, issuing one REST call per
+// distinct (limit, order, filter) so that limits and filters are applied in SQL.
+func FetchProductReviews(ctx context.Context, queries []ReviewListQuery) ([][]*models.Review, []error) {
+	return fetchReviewLists(ctx, "productIds", queries, func(r *models.Review) string { return r.ProductID })
+}
+
+// FetchUserReviewLists loads filtered, ordered review lists for many users
+func FetchUserReviewLists(ctx context.Context, queries []ReviewListQuery) ([][]*models.Review, []error) {
+	return fetchReviewLists(ctx, "userIds", queries, func(r *models.Review) string { return r.UserID })
+}
+
+func fetchReviewLists(ctx context.Context, ownerParam string, queries []ReviewListQuery, ownerOf func(*models.Review) string) ([][]*models.Review, []error) {
+	type batch struct {
+		limit  int
+		order  string
+		filter string
+	}
+	batches := make(map[batch][]string)
+	for _, q := range queries {
+		b := batch{limit: q.Limit, order: q.Order, filter: q.Filter}
+		batches[b] = append(batches[b], q.OwnerID)
+	}
+
+	// Map them properly since a product or user maps to Multiple Reviews
+	reviewMap := make(map[ReviewListQuery][]*models.Review)
+	for b, ownerIds := range batches {
+		url := fmt.Sprintf("http://localhost:8082/reviews?%s=%s&order=%s", ownerParam, strings.Join(ownerIds, ","), b.order)
+		if b.limit > 0 {
+			url += fmt.Sprintf("&perProductLimit=%d", b.limit)
+		}
+		if b.filter != "" {
+			url += "&" + b.filter
+		}
+		fmt.Printf("[Reviews Subgraph] Making REST call to: %s\n", url)
+		GetApiCounter(ctx).Increment("/reviews")
+		resp, err := http.Get(url)
+		if err != nil {
+			return nil, []error{fmt.Errorf("failed to fetch reviews: %v", err)}
+		}
+
+		var apiReviews []models.Review
+		if resp.StatusCode != http.StatusOK {
+			msg, _ := io.ReadAll(resp.Body)
+			err = fmt.Errorf("reviews API: %s", strings.TrimSpace(string(msg)))
+		} else {
+			err = json.NewDecoder(resp.Body).Decode(&apiReviews)
+		}
+		resp.Body.Close()
+		if err != nil {
+			return nil, []error{fmt.Errorf("failed to load reviews: %v", err)}
+		}
+
+		for i := range apiReviews {
+			key := ReviewListQuery{OwnerID: ownerOf(&apiReviews[i]), Limit: b.limit, Order: b.order, Filter: b.filter}
+			reviewMap[key] = append(reviewMap[key], &apiReviews[i])
+		}
+	}
+
+	results := make([][]*models.Review, len(queries))
+	errors := make([]error, len(queries))
+
+	for i, q := range queries {
+		// Dataloader protocol asserts array lengths remain constant! Ensure nil falls-back seamlessly to empty slice.
+		revs := reviewMap[q]
+		if revs == nil {
+			revs = []*models.Review{}
+		}
+		results[i] = revs
+	}
+
+	return results, errors
+}
+
+// FetchProductStats batches rating aggregate lookups for products
+func FetchProductStats(ctx context.Context, productIds []string) ([]*models.ProductRatingStats, []error) {
+	url := "http://localhost:8082/reviews/stats?productIds=" + strings.Join(productIds, ",")
 	fmt.Printf("[Reviews Subgraph] Making REST call to: %s\n", url)
-	GetApiCounter(ctx).Increment("/reviews")
+	GetApiCounter(ctx).Increment("/reviews/stats")
 	resp, err := http.Get(url)
 	if err != nil {
-		return nil, []error{fmt.Errorf("failed to fetch product reviews: %v", err)}
+		return nil, []error{fmt.Errorf("failed to fetch review stats: %v", err)}
 	}
 	defer resp.Body.Close()
 
-	var apiReviews []models.Review
-	if err := json.NewDecoder(resp.Body).Decode(&apiReviews); err != nil {
-		return nil, []error{fmt.Errorf("failed to decode reviews: %v", err)}
+	var apiStats []models.ProductRatingStats
+	if err := json.NewDecoder(resp.Body).Decode(&apiStats); err != nil {
+		return nil, []error{fmt.Errorf("failed to decode review stats: %v", err)}
 	}
 
-	// Map them properly since a product maps to Multiple Reviews
-	productReviewMap := make(map[string][]*models.Review)
-	for i := range apiReviews {
-		pID := apiReviews[i].ProductID
-		productReviewMap[pID] = append(productReviewMap[pID], &apiReviews[i])
+	statsMap := make(map[string]*models.ProductRatingStats)
+	for i := range apiStats {
+		statsMap[apiStats[i].ProductID] = &apiStats[i]
 	}
 
-	var results []*generated.Product
+	var results []*models.ProductRatingStats
 	errors := make([]error, len(productIds))
 
 	for _, id := range productIds {
-		// Dataloader protocol asserts array lengths remain constant! Ensure nil falls-back seamlessly to empty slice.
-		revs := productReviewMap[id]
-		if revs == nil {
-			revs = []*models.Review{}
+		stats, found := statsMap[id]
+		if !found {
+			stats = &models.ProductRatingStats{ProductID: id, Histogram: []*models.RatingBucket{}}
 		}
+		results = append(results, stats)
+	}
 
-		results = append(results, &generated.Product{
-			ID:      id,
-			Reviews: revs,
-		})
+	return results, errors
+}
+
+// FetchReviewVotes batches helpful/not-helpful vote count lookups for reviews
+func FetchReviewVotes(ctx context.Context, reviewIds []string) ([]*models.VoteCounts, []error) {
+	url := "http://localhost:8082/reviews/votes?reviewIds=" + strings.Join(reviewIds, ",")
+	fmt.Printf("[Reviews Subgraph] Making REST call to: %s\n", url)
+	GetApiCounter(ctx).Increment("/reviews/votes")
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to fetch review votes: %v", err)}
+	}
+	defer resp.Body.Close()
+
+	var apiCounts []models.VoteCounts
+	if err := json.NewDecoder(resp.Body).Decode(&apiCounts); err != nil {
+		return nil, []error{fmt.Errorf("failed to decode review votes: %v", err)}
+	}
+
+	countsMap := make(map[string]*models.VoteCounts)
+	for i := range apiCounts {
+		countsMap[apiCounts[i].ReviewID] = &apiCounts[i]
+	}
+
+	var results []*models.VoteCounts
+	errors := make([]error, len(reviewIds))
+
+	for _, id := range reviewIds {
+		counts, found := countsMap[id]
+		if !found {
+			counts = &models.VoteCounts{ReviewID: id}
+		}
+		results = append(results, counts)
+	}
+
+	return results, errors
+}
+
+// FetchReviewResponses batches official response lookups for reviews
+func FetchReviewResponses(ctx context.Context, reviewIds []string) ([]*models.OfficialResponse, []error) {
+	url := "http://localhost:8082/reviews/responses?reviewIds=" + strings.Join(reviewIds, ",")
+	fmt.Printf("[Reviews Subgraph] Making REST call to: %s\n", url)
+	GetApiCounter(ctx).Increment("/reviews/responses")
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to fetch review responses: %v", err)}
+	}
+	defer resp.Body.Close()
+
+	var apiResponses []models.OfficialResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResponses); err != nil {
+		return nil, []error{fmt.Errorf("failed to decode review responses: %v", err)}
+	}
+
+	responseMap := make(map[string]*models.OfficialResponse)
+	for i := range apiResponses {
+		responseMap[apiResponses[i].ReviewID] = &apiResponses[i]
+	}
+
+	var results []*models.OfficialResponse
+	errors := make([]error, len(reviewIds))
+
+	for _, id := range reviewIds {
+		// Most reviews have no response, which resolves to null.
+		results = append(results, responseMap[id])
+	}
+
+	return results, errors
+}
+
+// FetchReviewRevisions batches edit history lookups for reviews
+func FetchReviewRevisions(ctx context.Context, reviewIds []string) ([][]*models.Revision, []error) {
+	url := "http://localhost:8082/reviews/revisions?reviewIds=" + strings.Join(reviewIds, ",")
+	fmt.Printf("[Reviews Subgraph] Making REST call to: %s\n", url)
+	GetApiCounter(ctx).Increment("/reviews/revisions")
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to fetch review revisions: %v", err)}
+	}
+	defer resp.Body.Close()
+
+	var apiRevisions []models.Revision
+	if err := json.NewDecoder(resp.Body).Decode(&apiRevisions); err != nil {
+		return nil, []error{fmt.Errorf("failed to decode review revisions: %v", err)}
+	}
+
+	revisionMap := make(map[string][]*models.Revision)
+	for i := range apiRevisions {
+		rID := apiRevisions[i].ReviewID
+		revisionMap[rID] = append(revisionMap[rID], &apiRevisions[i])
+	}
+
+	results := make([][]*models.Revision, len(reviewIds))
+	errors := make([]error, len(reviewIds))
+
+	for i, id := range reviewIds {
+		revs := revisionMap[id]
+		if revs == nil {
+			revs = []*models.Revision{}
+		}
+		results[i] = revs
 	}
 
 	return results, errors
 }
 
-// FetchUserReviews counts review arrays mapped exclusively towards Users
+// FetchUserReviews counts the reviews written by each user
 func FetchUserReviews(ctx context.Context, userIds []string) ([]*generated.User, []error) {
 	url := "http://localhost:8082/reviews?userIds=" + strings.Join(userIds, ",")
 	fmt.Printf("[Reviews Subgraph] Making REST call to: %s\n", url)
@@ -141,11 +332,8 @@ func FetchUserReviews(ctx context.Context, userIds []string) ([]*generated.User,
 	}
 
 	userReviewCounter := make(map[string]int)
-	userReviewMap := make(map[string][]*models.Review)
 	for i := range apiReviews {
-		userID := apiReviews[i].UserID
-		userReviewCounter[userID]++
-		userReviewMap[userID] = append(userReviewMap[userID], &apiReviews[i])
+		userReviewCounter[apiReviews[i].UserID]++
 	}
 
 	var results []*generated.User
@@ -153,21 +341,261 @@ func FetchUserReviews(ctx context.Context, userIds []string) ([]*generated.User,
 
 	for _, id := range userIds {
 		count := userReviewCounter[id]
-		revs := userReviewMap[id]
-		if revs == nil {
-			revs = []*models.Review{}
-		}
-
 		results = append(results, &generated.User{
 			ID:           id,
 			TotalReviews: &count,
-			Reviews:      revs,
 		})
 	}
 
 	return results, errors
 }
 
+// FetchProductAspects batches aspect lookups for products
+func FetchProductAspects(ctx context.Context, productIds []string) ([][]*models.ReviewAspect, []error) {
+	url := "http://localhost:8082/reviews/aspects?productIds=" + strings.Join(productIds, ",")
+	fmt.Printf("[Reviews Subgraph] Making REST call to: %s\n", url)
+	GetApiCounter(ctx).Increment("/reviews/aspects")
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to fetch review aspects: %v", err)}
+	}
+	defer resp.Body.Close()
+
+	var apiAspects []models.ProductAspects
+	if err := json.NewDecoder(resp.Body).Decode(&apiAspects); err != nil {
+		return nil, []error{fmt.Errorf("failed to decode review aspects: %v", err)}
+	}
+
+	aspectMap := make(map[string][]*models.ReviewAspect)
+	for _, p := range apiAspects {
+		aspectMap[p.ProductID] = p.Aspects
+	}
+
+	results := make([][]*models.ReviewAspect, len(productIds))
+	errors := make([]error, len(productIds))
+
+	for i, id := range productIds {
+		aspects := aspectMap[id]
+		if aspects == nil {
+			aspects = []*models.ReviewAspect{}
+		}
+		results[i] = aspects
+	}
+
+	return results, errors
+}
+
+// FetchReviewDuplicates batches duplicate lookups for reviews. Reviews that are not
+// duplicates resolve to nil.
+func FetchReviewDuplicates(ctx context.Context, reviewIds []string) ([]*models.Duplicate, []error) {
+	url := "http://localhost:8082/reviews/duplicates?reviewIds=" + strings.Join(reviewIds, ",")
+	fmt.Printf("[Reviews Subgraph] Making REST call to: %s\n", url)
+	GetApiCounter(ctx).Increment("/reviews/duplicates")
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to fetch review duplicates: %v", err)}
+	}
+	defer resp.Body.Close()
+
+	var apiDuplicates []models.Duplicate
+	if err := json.NewDecoder(resp.Body).Decode(&apiDuplicates); err != nil {
+		return nil, []error{fmt.Errorf("failed to decode review duplicates: %v", err)}
+	}
+
+	duplicateMap := make(map[string]*models.Duplicate)
+	for i := range apiDuplicates {
+		duplicateMap[apiDuplicates[i].ReviewID] = &apiDuplicates[i]
+	}
+
+	results := make([]*models.Duplicate, len(reviewIds))
+	errors := make([]error, len(reviewIds))
+
+	for i, id := range reviewIds {
+		results[i] = duplicateMap[id]
+	}
+
+	return results, errors
+}
+
+// FetchReviewSummaries batches summary lookups for products. Products that have not
+// been summarised yet resolve to nil.
+func FetchReviewSummaries(ctx context.Context, productIds []string) ([]*models.ReviewSummary, []error) {
+	url := "http://localhost:8082/reviews/summaries?productIds=" + strings.Join(productIds, ",")
+	fmt.Printf("[Reviews Subgraph] Making REST call to: %s\n", url)
+	GetApiCounter(ctx).Increment("/reviews/summaries")
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to fetch review summaries: %v", err)}
+	}
+	defer resp.Body.Close()
+
+	var apiSummaries []*models.ReviewSummary
+	if err := json.NewDecoder(resp.Body).Decode(&apiSummaries); err != nil {
+		return nil, []error{fmt.Errorf("failed to decode review summaries: %v", err)}
+	}
+
+	summaryMap := make(map[string]*models.ReviewSummary)
+	for _, s := range apiSummaries {
+		if s != nil {
+			summaryMap[s.ProductID] = s
+		}
+	}
+
+	results := make([]*models.ReviewSummary, len(productIds))
+	errors := make([]error, len(productIds))
+
+	for i, id := range productIds {
+		results[i] = summaryMap[id]
+	}
+
+	return results, errors
+}
+
+// PurchaseKey identifies a user's purchase of a product
+type PurchaseKey struct {
+	UserID    string
+	ProductID string
+}
+
+// FetchVerifiedPurchases batches checks against the orders API of whether reviewers
+// bought the products they reviewed.
+func FetchVerifiedPurchases(ctx context.Context, keys []PurchaseKey) ([]bool, []error) {
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k.UserID + ":" + k.ProductID
+	}
+	url := "http://localhost:8083/orders/verified-purchases?pairs=" + strings.Join(pairs, ",")
+	fmt.Printf("[Reviews Subgraph] Making REST call to: %s\n", url)
+	GetApiCounter(ctx).Increment("/orders/verified-purchases")
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to fetch verified purchases: %v", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, []error{fmt.Errorf("failed to fetch verified purchases: orders API: %s", strings.TrimSpace(string(msg)))}
+	}
+
+	var apiPurchases []models.VerifiedPurchase
+	if err := json.NewDecoder(resp.Body).Decode(&apiPurchases); err != nil {
+		return nil, []error{fmt.Errorf("failed to decode verified purchases: %v", err)}
+	}
+
+	purchaseMap := make(map[PurchaseKey]bool)
+	for _, p := range apiPurchases {
+		purchaseMap[PurchaseKey{UserID: p.UserID, ProductID: p.ProductID}] = p.Verified
+	}
+
+	results := make([]bool, len(keys))
+	errors := make([]error, len(keys))
+
+	for i, k := range keys {
+		results[i] = purchaseMap[k]
+	}
+
+	return results, errors
+}
+
+// FetchQuestions fetches individual product questions by their IDs
+func FetchQuestions(ctx context.Context, ids []string) ([]*models.Question, []error) {
+	url := "http://localhost:8082/questions?ids=" + strings.Join(ids, ",")
+	fmt.Printf("[Reviews Subgraph] Making REST call to: %s\n", url)
+	GetApiCounter(ctx).Increment("/questions")
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to fetch questions: %v", err)}
+	}
+	defer resp.Body.Close()
+
+	var apiQuestions []models.Question
+	if err := json.NewDecoder(resp.Body).Decode(&apiQuestions); err != nil {
+		return nil, []error{fmt.Errorf("failed to decode questions: %v", err)}
+	}
+
+	questionMap := make(map[string]*models.Question)
+	for i := range apiQuestions {
+		questionMap[apiQuestions[i].ID] = &apiQuestions[i]
+	}
+
+	results := make([]*models.Question, len(ids))
+	errors := make([]error, len(ids))
+
+	for i, id := range ids {
+		results[i] = questionMap[id]
+	}
+
+	return results, errors
+}
+
+// FetchAnswers fetches individual answers by their IDs
+func FetchAnswers(ctx context.Context, ids []string) ([]*models.Answer, []error) {
+	apiAnswers, err := fetchAnswers(ctx, "ids", ids)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	answerMap := make(map[string]*models.Answer)
+	for i := range apiAnswers {
+		answerMap[apiAnswers[i].ID] = &apiAnswers[i]
+	}
+
+	results := make([]*models.Answer, len(ids))
+	errors := make([]error, len(ids))
+
+	for i, id := range ids {
+		results[i] = answerMap[id]
+	}
+
+	return results, errors
+}
+
+// FetchQuestionAnswers batches the answer lists of questions
+func FetchQuestionAnswers(ctx context.Context, questionIds []string) ([][]*models.Answer, []error) {
+	apiAnswers, err := fetchAnswers(ctx, "questionIds", questionIds)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	// The API keeps each question's answers in display order.
+	answerMap := make(map[string][]*models.Answer)
+	for i := range apiAnswers {
+		qID := apiAnswers[i].QuestionID
+		answerMap[qID] = append(answerMap[qID], &apiAnswers[i])
+	}
+
+	results := make([][]*models.Answer, len(questionIds))
+	errors := make([]error, len(questionIds))
+
+	for i, id := range questionIds {
+		answers := answerMap[id]
+		if answers == nil {
+			answers = []*models.Answer{}
+		}
+		results[i] = answers
+	}
+
+	return results, errors
+}
+
+func fetchAnswers(ctx context.Context, param string, ids []string) ([]models.Answer, error) {
+	url := "http://localhost:8082/answers?" + param + "=" + strings.Join(ids, ",")
+	fmt.Printf("[Reviews Subgraph] Making REST call to: %s\n", url)
+	GetApiCounter(ctx).Increment("/answers")
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch answers: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var apiAnswers []models.Answer
+	if err := json.NewDecoder(resp.Body).Decode(&apiAnswers); err != nil {
+		return nil, fmt.Errorf("failed to decode answers: %v", err)
+	}
+	return apiAnswers, nil
+}
+
 func DataLoaderMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		counter := &ApiCounter{counts: make(map[string]int)}
@@ -176,10 +604,34 @@ func DataLoaderMiddleware(next http.Handler) http.Handler {
 		reviewLoader := dataloadgen.NewLoader(FetchReviews)
 		prodReviewsLoader := dataloadgen.NewLoader(FetchProductReviews)
 		userReviewsLoader := dataloadgen.NewLoader(FetchUserReviews)
+		userReviewListLoader := dataloadgen.NewLoader(FetchUserReviewLists)
+		productStatsLoader := dataloadgen.NewLoader(FetchProductStats)
+		reviewVotesLoader := dataloadgen.NewLoader(FetchReviewVotes)
+		responsesLoader := dataloadgen.NewLoader(FetchReviewResponses)
+		revisionsLoader := dataloadgen.NewLoader(FetchReviewRevisions)
+		productAspectsLoader := dataloadgen.NewLoader(FetchProductAspects)
+		duplicatesLoader := dataloadgen.NewLoader(FetchReviewDuplicates)
+		summariesLoader := dataloadgen.NewLoader(FetchReviewSummaries)
+		purchasesLoader := dataloadgen.NewLoader(FetchVerifiedPurchases)
+		questionLoader := dataloadgen.NewLoader(FetchQuestions)
+		answerLoader := dataloadgen.NewLoader(FetchAnswers)
+		questionAnswersLoader := dataloadgen.NewLoader(FetchQuestionAnswers)
 
 		ctx = context.WithValue(ctx, ReviewKey, reviewLoader)
 		ctx = context.WithValue(ctx, ProductReviewsKey, prodReviewsLoader)
 		ctx = context.WithValue(ctx, UserReviewsKey, userReviewsLoader)
+		ctx = context.WithValue(ctx, UserReviewListKey, userReviewListLoader)
+		ctx = context.WithValue(ctx, ProductStatsKey, productStatsLoader)
+		ctx = context.WithValue(ctx, ReviewVotesKey, reviewVotesLoader)
+		ctx = context.WithValue(ctx, ResponsesKey, responsesLoader)
+		ctx = context.WithValue(ctx, RevisionsKey, revisionsLoader)
+		ctx = context.WithValue(ctx, ProductAspectsKey, productAspectsLoader)
+		ctx = context.WithValue(ctx, DuplicatesKey, duplicatesLoader)
+		ctx = context.WithValue(ctx, SummariesKey, summariesLoader)
+		ctx = context.WithValue(ctx, PurchasesKey, purchasesLoader)
+		ctx = context.WithValue(ctx, QuestionKey, questionLoader)
+		ctx = context.WithValue(ctx, AnswerKey, answerLoader)
+		ctx = context.WithValue(ctx, QuestionAnswersKey, questionAnswersLoader)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 
@@ -194,10 +646,58 @@ func CtxReviewProvider(ctx context.Context) *dataloadgen.Loader[string, *models.
 	return ctx.Value(ReviewKey).(*dataloadgen.Loader[string, *models.Review])
 }
 
-func CtxProdReviewProvider(ctx context.Context) *dataloadgen.Loader[string, *generated.Product] {
-	return ctx.Value(ProductReviewsKey).(*dataloadgen.Loader[string, *generated.Product])
+func CtxProdReviewProvider(ctx context.Context) *dataloadgen.Loader[ReviewListQuery, []*models.Review] {
+	return ctx.Value(ProductReviewsKey).(*dataloadgen.Loader[ReviewListQuery, []*models.Review])
 }
 
 func CtxUserReviewProvider(ctx context.Context) *dataloadgen.Loader[string, *generated.User] {
 	return ctx.Value(UserReviewsKey).(*dataloadgen.Loader[string, *generated.User])
 }
+
+func CtxUserReviewListProvider(ctx context.Context) *dataloadgen.Loader[ReviewListQuery, []*models.Review] {
+	return ctx.Value(UserReviewListKey).(*dataloadgen.Loader[ReviewListQuery, []*models.Review])
+}
+
+func CtxProductStatsProvider(ctx context.Context) *dataloadgen.Loader[string, *models.ProductRatingStats] {
+	return ctx.Value(ProductStatsKey).(*dataloadgen.Loader[string, *models.ProductRatingStats])
+}
+
+func CtxReviewVotesProvider(ctx context.Context) *dataloadgen.Loader[string, *models.VoteCounts] {
+	return ctx.Value(ReviewVotesKey).(*dataloadgen.Loader[string, *models.VoteCounts])
+}
+
+func CtxReviewResponseProvider(ctx context.Context) *dataloadgen.Loader[string, *models.OfficialResponse] {
+	return ctx.Value(ResponsesKey).(*dataloadgen.Loader[string, *models.OfficialResponse])
+}
+
+func CtxReviewRevisionsProvider(ctx context.Context) *dataloadgen.Loader[string, []*models.Revision] {
+	return ctx.Value(RevisionsKey).(*dataloadgen.Loader[string, []*models.Revision])
+}
+
+func CtxProductAspectsProvider(ctx context.Context) *dataloadgen.Loader[string, []*models.ReviewAspect] {
+	return ctx.Value(ProductAspectsKey).(*dataloadgen.Loader[string, []*models.ReviewAspect])
+}
+
+func CtxReviewDuplicatesProvider(ctx context.Context) *dataloadgen.Loader[string, *models.Duplicate] {
+	return ctx.Value(DuplicatesKey).(*dataloadgen.Loader[string, *models.Duplicate])
+}
+
+func CtxReviewSummaryProvider(ctx context.Context) *dataloadgen.Loader[string, *models.ReviewSummary] {
+	return ctx.Value(SummariesKey).(*dataloadgen.Loader[string, *models.ReviewSummary])
+}
+
+func CtxVerifiedPurchaseProvider(ctx context.Context) *dataloadgen.Loader[PurchaseKey, bool] {
+	return ctx.Value(PurchasesKey).(*dataloadgen.Loader[PurchaseKey, bool])
+}
+
+func CtxQuestionProvider(ctx context.Context) *dataloadgen.Loader[string, *models.Question] {
+	return ctx.Value(QuestionKey).(*dataloadgen.Loader[string, *models.Question])
+}
+
+func CtxAnswerProvider(ctx context.Context) *dataloadgen.Loader[string, *models.Answer] {
+	return ctx.Value(AnswerKey).(*dataloadgen.Loader[string, *models.Answer])
+}
+
+func CtxQuestionAnswersProvider(ctx context.Context) *dataloadgen.Loader[string, []*models.Answer] {
+	return ctx.Value(QuestionAnswersKey).(*dataloadgen.Loader[string, []*models.Answer])
+}
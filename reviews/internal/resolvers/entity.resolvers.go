@@ -11,9 +11,20 @@ import (
 	"product-reviews/internal/review/models"
 )
 
+// FindAnswerByID is the resolver for the findAnswerByID field.
+func (r *entityResolver) FindAnswerByID(ctx context.Context, id string) (*models.Answer, error) {
+	return CtxAnswerProvider(ctx).Load(ctx, id)
+}
+
 // FindProductByID is the resolver for the findProductByID field.
 func (r *entityResolver) FindProductByID(ctx context.Context, id string) (*generated.Product, error) {
-	return CtxProdReviewProvider(ctx).Load(ctx, id)
+	// Review lists depend on field arguments, so they are loaded by the Product field resolvers.
+	return &generated.Product{ID: id}, nil
+}
+
+// FindQuestionByID is the resolver for the findQuestionByID field.
+func (r *entityResolver) FindQuestionByID(ctx context.Context, id string) (*models.Question, error) {
+	return CtxQuestionProvider(ctx).Load(ctx, id)
 }
 
 // FindReviewByID is the resolver for the findReviewByID field.
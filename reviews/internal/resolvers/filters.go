@@ -0,0 +1,65 @@
+package resolvers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"product-reviews/internal/generated"
+)
+
+// newReviewListQuery builds the dataloader key for a review list owned by ownerID,
+// encoding the GraphQL filter as the query parameters understood by GET /reviews.
+func newReviewListQuery(ownerID string, limit int, filter *generated.ReviewFilter, orderBy *generated.ReviewOrder) (ReviewListQuery, error) {
+	query := ReviewListQuery{OwnerID: ownerID, Limit: limit, Order: "newest"}
+	if orderBy != nil {
+		if !orderBy.IsValid() {
+			return query, fmt.Errorf("unknown review order %q", *orderBy)
+		}
+		query.Order = strings.ToLower(orderBy.String())
+	}
+
+	if filter == nil {
+		return query, nil
+	}
+
+	params := url.Values{}
+	for _, f := range []struct {
+		name  string
+		value *int
+	}{
+		{"minRating", filter.MinRating},
+		{"maxRating", filter.MaxRating},
+	} {
+		if f.value == nil {
+			continue
+		}
+		if *f.value < 1 || *f.value > 5 {
+			return query, fmt.Errorf("%s must be between 1 and 5", f.name)
+		}
+		params.Set(f.name, strconv.Itoa(*f.value))
+	}
+	if filter.CreatedAfter != nil {
+		params.Set("createdAfter", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		params.Set("createdBefore", *filter.CreatedBefore)
+	}
+	if filter.HasBody != nil {
+		params.Set("hasBody", strconv.FormatBool(*filter.HasBody))
+	}
+	if filter.RatingMismatch != nil {
+		params.Set("ratingMismatch", strconv.FormatBool(*filter.RatingMismatch))
+	}
+	if filter.Aspect != nil {
+		params.Set("aspect", *filter.Aspect)
+	}
+	if filter.Sentiment != nil {
+		params.Set("sentiment", string(*filter.Sentiment))
+	}
+
+	// Encode sorts the keys, so equal filters always produce equal dataloader keys.
+	query.Filter = params.Encode()
+	return query, nil
+}
@@ -0,0 +1,116 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"product-reviews/internal/generated"
+	"product-reviews/internal/review/models"
+)
+
+const (
+	defaultPageSize = 10
+	maxPageSize     = 100
+)
+
+// fetchReviewsConnection loads one page of reviews from a keyset-paginated REST listing
+// such as /products/{id}/reviews.
+func fetchReviewsConnection(ctx context.Context, path string, first *int, after *string, last *int, before *string) (*generated.ReviewConnection, error) {
+	reviews, pageInfo, err := fetchPage(ctx, path, first, after, last, before, func(r *models.Review) string { return r.Cursor })
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]*generated.ReviewEdge, 0, len(reviews))
+	for _, rev := range reviews {
+		edges = append(edges, &generated.ReviewEdge{Cursor: rev.Cursor, Node: rev})
+	}
+	return &generated.ReviewConnection{Edges: edges, PageInfo: pageInfo}, nil
+}
+
+// fetchPage loads one page of items from a keyset-paginated REST listing. One item
+// beyond the requested page size is fetched so that hasNextPage/hasPreviousPage can be
+// answered without a separate count query.
+func fetchPage[T any](ctx context.Context, path string, first *int, after *string, last *int, before *string, cursor func(T) string) ([]T, *generated.PageInfo, error) {
+	if first != nil && last != nil {
+		return nil, nil, errors.New("first and last cannot be combined")
+	}
+
+	backward := last != nil
+	size := defaultPageSize
+	if first != nil {
+		size = *first
+	} else if last != nil {
+		size = *last
+	}
+	if size < 0 {
+		return nil, nil, errors.New("page size must not be negative")
+	}
+	if size > maxPageSize {
+		size = maxPageSize
+	}
+
+	q := url.Values{}
+	if backward {
+		q.Set("last", strconv.Itoa(size+1))
+	} else {
+		q.Set("first", strconv.Itoa(size+1))
+	}
+	if after != nil {
+		q.Set("after", *after)
+	}
+	if before != nil {
+		q.Set("before", *before)
+	}
+
+	var items []T
+	if err := callReviewsAPI(ctx, http.MethodGet, path+"?"+q.Encode(), nil, &items); err != nil {
+		return nil, nil, err
+	}
+
+	pageInfo := &generated.PageInfo{
+		// Relay allows reporting the opposite direction as a best effort, so assume there
+		// is more on that side whenever a cursor was supplied.
+		HasPreviousPage: !backward && after != nil,
+		HasNextPage:     backward && before != nil,
+	}
+
+	hasMore := len(items) > size
+	if backward {
+		pageInfo.HasPreviousPage = hasMore
+		if hasMore {
+			items = items[len(items)-size:]
+		}
+	} else {
+		pageInfo.HasNextPage = hasMore
+		if hasMore {
+			items = items[:size]
+		}
+	}
+
+	if len(items) > 0 {
+		start, end := cursor(items[0]), cursor(items[len(items)-1])
+		pageInfo.StartCursor = &start
+		pageInfo.EndCursor = &end
+	}
+
+	return items, pageInfo, nil
+}
+
+// fetchQuestionsConnection loads one page of a product's questions.
+func fetchQuestionsConnection(ctx context.Context, productID string, first *int, after *string, last *int, before *string) (*generated.QuestionConnection, error) {
+	path := "/products/" + url.PathEscape(productID) + "/questions"
+	questions, pageInfo, err := fetchPage(ctx, path, first, after, last, before, func(q *models.Question) string { return q.Cursor })
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]*generated.QuestionEdge, 0, len(questions))
+	for _, q := range questions {
+		edges = append(edges, &generated.QuestionEdge{Cursor: q.Cursor, Node: q})
+	}
+	return &generated.QuestionConnection{Edges: edges, PageInfo: pageInfo}, nil
+}
@@ -0,0 +1,59 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"product-reviews/internal/review/models"
+)
+
+var (
+	errQuestionNotFound = errors.New("question not found")
+	errAnswerNotFound   = errors.New("answer not found")
+)
+
+// questionOfViewer loads a question the caller asked, whatever its status. With
+// allowAdmin set, admins may act on any question too.
+func questionOfViewer(ctx context.Context, id string, allowAdmin bool) (*models.Question, error) {
+	viewer, err := CtxViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The loader only sees approved questions, but authors may act on pending ones.
+	var questions []*models.Question
+	if err := callReviewsAPI(ctx, http.MethodGet, "/questions?status=all&ids="+url.QueryEscape(id), nil, &questions); err != nil {
+		return nil, err
+	}
+	if len(questions) == 0 {
+		return nil, errQuestionNotFound
+	}
+	question := questions[0]
+	if question.UserID != viewer.UserID && !(allowAdmin && viewer.HasRole(RoleAdmin)) {
+		return nil, errForbidden
+	}
+	return question, nil
+}
+
+// answerOfViewer loads an answer the caller wrote, or any answer if the caller is an
+// admin, whatever its status.
+func answerOfViewer(ctx context.Context, id string) (*models.Answer, error) {
+	viewer, err := CtxViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var answers []*models.Answer
+	if err := callReviewsAPI(ctx, http.MethodGet, "/answers?status=all&ids="+url.QueryEscape(id), nil, &answers); err != nil {
+		return nil, err
+	}
+	if len(answers) == 0 {
+		return nil, errAnswerNotFound
+	}
+	answer := answers[0]
+	if answer.UserID != viewer.UserID && !viewer.HasRole(RoleAdmin) {
+		return nil, errForbidden
+	}
+	return answer, nil
+}
@@ -0,0 +1,27 @@
+package resolvers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"product-reviews/internal/review/models"
+)
+
+// closeReviewReport resolves or dismisses an open report on behalf of the calling admin.
+func closeReviewReport(ctx context.Context, id, action string, note *string) (*models.ReviewReport, error) {
+	admin, err := CtxAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	decision := map[string]string{"moderatorId": admin.UserID}
+	if note != nil {
+		decision["note"] = *note
+	}
+
+	var closed models.ReviewReport
+	if err := callReviewsAPI(ctx, http.MethodPost, "/reviews/reports/"+url.PathEscape(id)+"/"+action, decision, &closed); err != nil {
+		return nil, err
+	}
+	return &closed, nil
+}
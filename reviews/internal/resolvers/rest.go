@@ -0,0 +1,81 @@
+package resolvers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const reviewsAPI = "http://localhost:8082"
+
+// apiError turns the body of a failed API response into an error. Structured errors,
+// such as the conflict returned when a user reviews the same product twice, keep their
+// code and details as GraphQL error extensions so clients can act on them.
+func apiError(msg []byte) error {
+	var structured map[string]any
+	if json.Unmarshal(msg, &structured) == nil {
+		if text, ok := structured["error"].(string); ok {
+			delete(structured, "error")
+			return &gqlerror.Error{Message: "reviews API: " + text, Extensions: structured}
+		}
+	}
+	return fmt.Errorf("reviews API: %s", strings.TrimSpace(string(msg)))
+}
+
+// callReviewsAPI sends a request with an optional JSON payload to the reviews REST API
+// and decodes the JSON response into out. Non-2xx responses are returned as errors
+// carrying the message written by the API.
+func callReviewsAPI(ctx context.Context, method, path string, payload any, out any) error {
+	var body io.Reader
+	if payload != nil {
+		buf, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %v", err)
+		}
+		body = bytes.NewReader(buf)
+	}
+
+	url := reviewsAPI + path
+	fmt.Printf("[Reviews Subgraph] Making REST call to: %s %s\n", method, url)
+	GetApiCounter(ctx).Increment(path)
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %v", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	// The API checks the role itself for admin-only endpoints such as moderation, so the
+	// caller's identity is passed on together with the gateway secret.
+	if viewer, err := CtxViewer(ctx); err == nil {
+		req.Header.Set("X-User-Id", viewer.UserID)
+		req.Header.Set("X-User-Roles", strings.Join(viewer.Roles, ","))
+		req.Header.Set("X-Gateway-Secret", gatewaySecret)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call reviews API: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(resp.Body)
+		return apiError(msg)
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %v", err)
+	}
+	return nil
+}
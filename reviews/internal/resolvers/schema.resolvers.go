@@ -7,10 +7,525 @@ package resolvers
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/url"
 	"product-reviews/internal/generated"
 	"product-reviews/internal/review/models"
 )
 
+// Author is the resolver for the author field.
+func (r *answerResolver) Author(ctx context.Context, obj *models.Answer) (*generated.User, error) {
+	return &generated.User{ID: obj.UserID}, nil
+}
+
+// Question is the resolver for the question field.
+func (r *answerResolver) Question(ctx context.Context, obj *models.Answer) (*models.Question, error) {
+	return CtxQuestionProvider(ctx).Load(ctx, obj.QuestionID)
+}
+
+// CreateReview is the resolver for the createReview field.
+func (r *mutationResolver) CreateReview(ctx context.Context, input generated.CreateReviewInput) (*models.Review, error) {
+	viewer, err := CtxViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	review := models.Review{
+		ProductID: input.ProductID,
+		UserID:    viewer.UserID,
+		Body:      input.Body,
+		Rating:    input.Rating,
+	}
+
+	var created models.Review
+	if err := callReviewsAPI(ctx, http.MethodPost, "/reviews", review, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateReview is the resolver for the updateReview field.
+func (r *mutationResolver) UpdateReview(ctx context.Context, id string, input generated.UpdateReviewInput) (*models.Review, error) {
+	viewer, err := CtxViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := reviewOfViewer(ctx, id); err != nil {
+		return nil, err
+	}
+
+	update := struct {
+		generated.UpdateReviewInput
+		EditorID string `json:"editorId"`
+	}{UpdateReviewInput: input, EditorID: viewer.UserID}
+
+	var updated models.Review
+	if err := callReviewsAPI(ctx, http.MethodPut, "/reviews/"+url.PathEscape(id), update, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteReview is the resolver for the deleteReview field.
+func (r *mutationResolver) DeleteReview(ctx context.Context, id string) (*models.Review, error) {
+	// The review is loaded first so the deleted entity can be handed back to the client.
+	deleted, err := reviewOfViewer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := callReviewsAPI(ctx, http.MethodDelete, "/reviews/"+url.PathEscape(id), nil, nil); err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// UpsertMyReview is the resolver for the upsertMyReview field.
+func (r *mutationResolver) UpsertMyReview(ctx context.Context, input generated.UpsertMyReviewInput) (*models.Review, error) {
+	viewer, err := CtxViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	path := "/products/" + url.PathEscape(input.ProductID) + "/reviews/" + url.PathEscape(viewer.UserID)
+	review := struct {
+		Body   string `json:"body"`
+		Rating int    `json:"rating"`
+	}{Body: input.Body, Rating: input.Rating}
+
+	var saved models.Review
+	if err := callReviewsAPI(ctx, http.MethodPut, path, review, &saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// VoteReview is the resolver for the voteReview field.
+func (r *mutationResolver) VoteReview(ctx context.Context, reviewID string, helpful bool) (*models.Review, error) {
+	viewer, err := CtxViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	path := "/reviews/" + url.PathEscape(reviewID) + "/votes/" + url.PathEscape(viewer.UserID)
+	vote := map[string]bool{"helpful": helpful}
+	if err := callReviewsAPI(ctx, http.MethodPut, path, vote, nil); err != nil {
+		return nil, err
+	}
+	return CtxReviewProvider(ctx).Load(ctx, reviewID)
+}
+
+// RetractReviewVote is the resolver for the retractReviewVote field.
+func (r *mutationResolver) RetractReviewVote(ctx context.Context, reviewID string) (*models.Review, error) {
+	viewer, err := CtxViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	path := "/reviews/" + url.PathEscape(reviewID) + "/votes/" + url.PathEscape(viewer.UserID)
+	if err := callReviewsAPI(ctx, http.MethodDelete, path, nil, nil); err != nil {
+		return nil, err
+	}
+	return CtxReviewProvider(ctx).Load(ctx, reviewID)
+}
+
+// RespondToReview is the resolver for the respondToReview field.
+func (r *mutationResolver) RespondToReview(ctx context.Context, reviewID string, body string) (*models.Review, error) {
+	merchant, err := CtxMerchant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := models.OfficialResponse{ResponderID: merchant.UserID, Body: body}
+	if err := callReviewsAPI(ctx, http.MethodPost, "/reviews/"+url.PathEscape(reviewID)+"/response", response, nil); err != nil {
+		return nil, err
+	}
+	return CtxReviewProvider(ctx).Load(ctx, reviewID)
+}
+
+// UpdateReviewResponse is the resolver for the updateReviewResponse field.
+func (r *mutationResolver) UpdateReviewResponse(ctx context.Context, reviewID string, body string) (*models.Review, error) {
+	merchant, err := CtxMerchant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := models.OfficialResponse{ResponderID: merchant.UserID, Body: body}
+	if err := callReviewsAPI(ctx, http.MethodPut, "/reviews/"+url.PathEscape(reviewID)+"/response", response, nil); err != nil {
+		return nil, err
+	}
+	return CtxReviewProvider(ctx).Load(ctx, reviewID)
+}
+
+// ModerateReview is the resolver for the moderateReview field.
+func (r *mutationResolver) ModerateReview(ctx context.Context, id string, status models.ReviewStatus, reason *string) (*models.Review, error) {
+	admin, err := CtxAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := map[models.ReviewStatus]string{
+		models.ReviewStatusApproved: "approve",
+		models.ReviewStatusRejected: "reject",
+		models.ReviewStatusHidden:   "hide",
+	}
+	action, ok := actions[status]
+	if !ok {
+		return nil, fmt.Errorf("reviews cannot be moved back to %s", status)
+	}
+
+	decision := map[string]string{"moderatorId": admin.UserID}
+	if reason != nil {
+		decision["reason"] = *reason
+	}
+
+	var moderated models.Review
+	if err := callReviewsAPI(ctx, http.MethodPost, "/reviews/"+url.PathEscape(id)+"/"+action, decision, &moderated); err != nil {
+		return nil, err
+	}
+	return &moderated, nil
+}
+
+// ReportReview is the resolver for the reportReview field.
+func (r *mutationResolver) ReportReview(ctx context.Context, reviewID string, reason models.ReportReason, details *string) (*models.ReviewReport, error) {
+	viewer, err := CtxViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := models.ReviewReport{ReporterID: viewer.UserID, Reason: reason, Details: details}
+	var created models.ReviewReport
+	if err := callReviewsAPI(ctx, http.MethodPost, "/reviews/"+url.PathEscape(reviewID)+"/reports", report, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// ResolveReviewReport is the resolver for the resolveReviewReport field.
+func (r *mutationResolver) ResolveReviewReport(ctx context.Context, id string, note *string) (*models.ReviewReport, error) {
+	return closeReviewReport(ctx, id, "resolve", note)
+}
+
+// DismissReviewReport is the resolver for the dismissReviewReport field.
+func (r *mutationResolver) DismissReviewReport(ctx context.Context, id string, note *string) (*models.ReviewReport, error) {
+	return closeReviewReport(ctx, id, "dismiss", note)
+}
+
+// AskQuestion is the resolver for the askQuestion field.
+func (r *mutationResolver) AskQuestion(ctx context.Context, productID string, body string) (*models.Question, error) {
+	viewer, err := CtxViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	question := map[string]string{"userId": viewer.UserID, "body": body}
+	var created models.Question
+	if err := callReviewsAPI(ctx, http.MethodPost, "/products/"+url.PathEscape(productID)+"/questions", question, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// DeleteQuestion is the resolver for the deleteQuestion field.
+func (r *mutationResolver) DeleteQuestion(ctx context.Context, id string) (*models.Question, error) {
+	question, err := questionOfViewer(ctx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := callReviewsAPI(ctx, http.MethodDelete, "/questions/"+url.PathEscape(id), nil, nil); err != nil {
+		return nil, err
+	}
+	return question, nil
+}
+
+// AnswerQuestion is the resolver for the answerQuestion field.
+func (r *mutationResolver) AnswerQuestion(ctx context.Context, questionID string, body string) (*models.Answer, error) {
+	viewer, err := CtxViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	answer := map[string]string{"userId": viewer.UserID, "body": body}
+	var created models.Answer
+	if err := callReviewsAPI(ctx, http.MethodPost, "/questions/"+url.PathEscape(questionID)+"/answers", answer, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// DeleteAnswer is the resolver for the deleteAnswer field.
+func (r *mutationResolver) DeleteAnswer(ctx context.Context, id string) (*models.Answer, error) {
+	answer, err := answerOfViewer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := callReviewsAPI(ctx, http.MethodDelete, "/answers/"+url.PathEscape(id), nil, nil); err != nil {
+		return nil, err
+	}
+	return answer, nil
+}
+
+// UpvoteAnswer is the resolver for the upvoteAnswer field.
+func (r *mutationResolver) UpvoteAnswer(ctx context.Context, answerID string) (*models.Answer, error) {
+	viewer, err := CtxViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var answer models.Answer
+	path := "/answers/" + url.PathEscape(answerID) + "/upvotes/" + url.PathEscape(viewer.UserID)
+	if err := callReviewsAPI(ctx, http.MethodPut, path, nil, &answer); err != nil {
+		return nil, err
+	}
+	return &answer, nil
+}
+
+// RetractAnswerUpvote is the resolver for the retractAnswerUpvote field.
+func (r *mutationResolver) RetractAnswerUpvote(ctx context.Context, answerID string) (*models.Answer, error) {
+	viewer, err := CtxViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var answer models.Answer
+	path := "/answers/" + url.PathEscape(answerID) + "/upvotes/" + url.PathEscape(viewer.UserID)
+	if err := callReviewsAPI(ctx, http.MethodDelete, path, nil, &answer); err != nil {
+		return nil, err
+	}
+	return &answer, nil
+}
+
+// AcceptAnswer is the resolver for the acceptAnswer field.
+func (r *mutationResolver) AcceptAnswer(ctx context.Context, answerID string) (*models.Question, error) {
+	answer, err := CtxAnswerProvider(ctx).Load(ctx, answerID)
+	if err != nil {
+		return nil, err
+	}
+	if answer == nil {
+		return nil, errAnswerNotFound
+	}
+	if _, err := questionOfViewer(ctx, answer.QuestionID, false); err != nil {
+		return nil, err
+	}
+
+	var question models.Question
+	path := "/questions/" + url.PathEscape(answer.QuestionID) + "/accepted-answer"
+	if err := callReviewsAPI(ctx, http.MethodPut, path, map[string]string{"answerId": answerID}, &question); err != nil {
+		return nil, err
+	}
+	// The answer was loaded before it was accepted; drop it so acceptedAnswer reloads it.
+	CtxAnswerProvider(ctx).Clear(answerID)
+	return &question, nil
+}
+
+// UnacceptAnswer is the resolver for the unacceptAnswer field.
+func (r *mutationResolver) UnacceptAnswer(ctx context.Context, questionID string) (*models.Question, error) {
+	if _, err := questionOfViewer(ctx, questionID, false); err != nil {
+		return nil, err
+	}
+
+	var question models.Question
+	if err := callReviewsAPI(ctx, http.MethodDelete, "/questions/"+url.PathEscape(questionID)+"/accepted-answer", nil, &question); err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+// Responder is the resolver for the responder field.
+func (r *officialResponseResolver) Responder(ctx context.Context, obj *models.OfficialResponse) (*generated.User, error) {
+	return &generated.User{ID: obj.ResponderID}, nil
+}
+
+// Reviews is the resolver for the reviews field.
+func (r *productResolver) Reviews(ctx context.Context, obj *generated.Product, first *int, filter *generated.ReviewFilter, orderBy *generated.ReviewOrder) ([]*models.Review, error) {
+	limit := 0
+	if first != nil {
+		if *first < 0 {
+			return nil, fmt.Errorf("first must not be negative")
+		}
+		if *first == 0 {
+			return []*models.Review{}, nil
+		}
+		limit = *first
+	}
+
+	query, err := newReviewListQuery(obj.ID, limit, filter, orderBy)
+	if err != nil {
+		return nil, err
+	}
+	return CtxProdReviewProvider(ctx).Load(ctx, query)
+}
+
+// ReviewsConnection is the resolver for the reviewsConnection field.
+func (r *productResolver) ReviewsConnection(ctx context.Context, obj *generated.Product, first *int, after *string, last *int, before *string) (*generated.ReviewConnection, error) {
+	return fetchReviewsConnection(ctx, "/products/"+url.PathEscape(obj.ID)+"/reviews", first, after, last, before)
+}
+
+// AverageRating is the resolver for the averageRating field.
+func (r *productResolver) AverageRating(ctx context.Context, obj *generated.Product) (*float64, error) {
+	stats, err := CtxProductStatsProvider(ctx).Load(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return stats.AverageRating, nil
+}
+
+// ReviewCount is the resolver for the reviewCount field.
+func (r *productResolver) ReviewCount(ctx context.Context, obj *generated.Product) (int, error) {
+	stats, err := CtxProductStatsProvider(ctx).Load(ctx, obj.ID)
+	if err != nil {
+		return 0, err
+	}
+	return stats.ReviewCount, nil
+}
+
+// RatingHistogram is the resolver for the ratingHistogram field.
+func (r *productResolver) RatingHistogram(ctx context.Context, obj *generated.Product) ([]*models.RatingBucket, error) {
+	stats, err := CtxProductStatsProvider(ctx).Load(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return stats.Histogram, nil
+}
+
+// SentimentBreakdown is the resolver for the sentimentBreakdown field.
+func (r *productResolver) SentimentBreakdown(ctx context.Context, obj *generated.Product) (*models.SentimentBreakdown, error) {
+	stats, err := CtxProductStatsProvider(ctx).Load(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &stats.Sentiment, nil
+}
+
+// ReviewAspects is the resolver for the reviewAspects field.
+func (r *productResolver) ReviewAspects(ctx context.Context, obj *generated.Product, first *int) ([]*models.ReviewAspect, error) {
+	aspects, err := CtxProductAspectsProvider(ctx).Load(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	if first != nil && *first >= 0 && *first < len(aspects) {
+		aspects = aspects[:*first]
+	}
+	return aspects, nil
+}
+
+// ReviewSummary is the resolver for the reviewSummary field.
+func (r *productResolver) ReviewSummary(ctx context.Context, obj *generated.Product) (*models.ReviewSummary, error) {
+	return CtxReviewSummaryProvider(ctx).Load(ctx, obj.ID)
+}
+
+// Questions is the resolver for the questions field.
+func (r *productResolver) Questions(ctx context.Context, obj *generated.Product, first *int, after *string, last *int, before *string) (*generated.QuestionConnection, error) {
+	return fetchQuestionsConnection(ctx, obj.ID, first, after, last, before)
+}
+
+// ModerationQueue is the resolver for the moderationQueue field.
+func (r *queryResolver) ModerationQueue(ctx context.Context, first *int) ([]*models.Review, error) {
+	if _, err := CtxAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	limit := 50
+	if first != nil {
+		limit = *first
+	}
+	if limit < 1 {
+		return []*models.Review{}, nil
+	}
+
+	var pending []*models.Review
+	path := fmt.Sprintf("/reviews?status=%s&order=oldest&limit=%d", models.ReviewStatusPending, limit)
+	if err := callReviewsAPI(ctx, http.MethodGet, path, nil, &pending); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// DuplicateReviewClusters is the resolver for the duplicateReviewClusters field.
+func (r *queryResolver) DuplicateReviewClusters(ctx context.Context, first *int, minSize *int) ([]*models.DuplicateCluster, error) {
+	if _, err := CtxAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	limit, size := 20, 2
+	if first != nil {
+		limit = *first
+	}
+	if minSize != nil {
+		size = max(*minSize, 2)
+	}
+	if limit < 1 {
+		return []*models.DuplicateCluster{}, nil
+	}
+
+	var clusters []*models.DuplicateCluster
+	path := fmt.Sprintf("/reviews/duplicate-clusters?limit=%d&minSize=%d", limit, size)
+	if err := callReviewsAPI(ctx, http.MethodGet, path, nil, &clusters); err != nil {
+		return nil, err
+	}
+	return clusters, nil
+}
+
+// ReviewReports is the resolver for the reviewReports field.
+func (r *queryResolver) ReviewReports(ctx context.Context, status *models.ReportStatus, reason *models.ReportReason, first *int) ([]*models.ReviewReport, error) {
+	if _, err := CtxAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	limit := 50
+	if first != nil {
+		limit = *first
+	}
+	if limit < 1 {
+		return []*models.ReviewReport{}, nil
+	}
+
+	params := url.Values{}
+	params.Set("limit", fmt.Sprint(limit))
+	if status != nil {
+		params.Set("status", string(*status))
+	} else {
+		params.Set("status", "all")
+	}
+	if reason != nil {
+		params.Set("reason", string(*reason))
+	}
+
+	var reports []*models.ReviewReport
+	if err := callReviewsAPI(ctx, http.MethodGet, "/reviews/reports?"+params.Encode(), nil, &reports); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// SearchReviews is the resolver for the searchReviews field.
+func (r *queryResolver) SearchReviews(ctx context.Context, query string, productID *string, minRating *int, first *int, after *string) (*generated.ReviewSearchConnection, error) {
+	return fetchReviewSearch(ctx, query, productID, minRating, first, after)
+}
+
+// Author is the resolver for the author field.
+func (r *questionResolver) Author(ctx context.Context, obj *models.Question) (*generated.User, error) {
+	return &generated.User{ID: obj.UserID}, nil
+}
+
+// Product is the resolver for the product field.
+func (r *questionResolver) Product(ctx context.Context, obj *models.Question) (*generated.Product, error) {
+	return &generated.Product{ID: obj.ProductID}, nil
+}
+
+// Answers is the resolver for the answers field.
+func (r *questionResolver) Answers(ctx context.Context, obj *models.Question) ([]*models.Answer, error) {
+	return CtxQuestionAnswersProvider(ctx).Load(ctx, obj.ID)
+}
+
+// AcceptedAnswer is the resolver for the acceptedAnswer field.
+func (r *questionResolver) AcceptedAnswer(ctx context.Context, obj *models.Question) (*models.Answer, error) {
+	if obj.AcceptedAnswerID == "" {
+		return nil, nil
+	}
+	return CtxAnswerProvider(ctx).Load(ctx, obj.AcceptedAnswerID)
+}
+
 // Author is the resolver for the author field.
 func (r *reviewResolver) Author(ctx context.Context, obj *models.Review) (*generated.User, error) {
 	return &generated.User{ID: obj.UserID}, nil
@@ -21,7 +536,141 @@ func (r *reviewResolver) Product(ctx context.Context, obj *models.Review) (*gene
 	return &generated.Product{ID: obj.ProductID}, nil
 }
 
+// HelpfulCount is the resolver for the helpfulCount field.
+func (r *reviewResolver) HelpfulCount(ctx context.Context, obj *models.Review) (int, error) {
+	votes, err := CtxReviewVotesProvider(ctx).Load(ctx, obj.ID)
+	if err != nil {
+		return 0, err
+	}
+	return votes.HelpfulCount, nil
+}
+
+// UnhelpfulCount is the resolver for the unhelpfulCount field.
+func (r *reviewResolver) UnhelpfulCount(ctx context.Context, obj *models.Review) (int, error) {
+	votes, err := CtxReviewVotesProvider(ctx).Load(ctx, obj.ID)
+	if err != nil {
+		return 0, err
+	}
+	return votes.UnhelpfulCount, nil
+}
+
+// OfficialResponse is the resolver for the officialResponse field.
+func (r *reviewResolver) OfficialResponse(ctx context.Context, obj *models.Review) (*models.OfficialResponse, error) {
+	return CtxReviewResponseProvider(ctx).Load(ctx, obj.ID)
+}
+
+// IsEdited is the resolver for the isEdited field.
+func (r *reviewResolver) IsEdited(ctx context.Context, obj *models.Review) (bool, error) {
+	return obj.EditedAt != nil, nil
+}
+
+// Revisions is the resolver for the revisions field.
+func (r *reviewResolver) Revisions(ctx context.Context, obj *models.Review) ([]*models.Revision, error) {
+	viewer, err := CtxViewer(ctx)
+	if err != nil || (viewer.UserID != obj.UserID && !viewer.HasRole(RoleAdmin)) {
+		return nil, nil
+	}
+	return CtxReviewRevisionsProvider(ctx).Load(ctx, obj.ID)
+}
+
+// DuplicateOf is the resolver for the duplicateOf field.
+func (r *reviewResolver) DuplicateOf(ctx context.Context, obj *models.Review) (*models.Review, error) {
+	duplicate, err := CtxReviewDuplicatesProvider(ctx).Load(ctx, obj.ID)
+	if err != nil || duplicate == nil {
+		return nil, err
+	}
+	return CtxReviewProvider(ctx).Load(ctx, duplicate.OriginalID)
+}
+
+// VerifiedPurchase is the resolver for the verifiedPurchase field.
+func (r *reviewResolver) VerifiedPurchase(ctx context.Context, obj *models.Review) (bool, error) {
+	return CtxVerifiedPurchaseProvider(ctx).Load(ctx, PurchaseKey{UserID: obj.UserID, ProductID: obj.ProductID})
+}
+
+// Reporter is the resolver for the reporter field.
+func (r *reviewReportResolver) Reporter(ctx context.Context, obj *models.ReviewReport) (*generated.User, error) {
+	return &generated.User{ID: obj.ReporterID}, nil
+}
+
+// ResolvedBy is the resolver for the resolvedBy field.
+func (r *reviewReportResolver) ResolvedBy(ctx context.Context, obj *models.ReviewReport) (*generated.User, error) {
+	if obj.ResolvedByID == "" {
+		return nil, nil
+	}
+	return &generated.User{ID: obj.ResolvedByID}, nil
+}
+
+// Editor is the resolver for the editor field.
+func (r *reviewRevisionResolver) Editor(ctx context.Context, obj *models.Revision) (*generated.User, error) {
+	return &generated.User{ID: obj.EditorID}, nil
+}
+
+// Review is the resolver for the review field.
+func (r *summarySentenceResolver) Review(ctx context.Context, obj *models.SummarySentence) (*models.Review, error) {
+	return CtxReviewProvider(ctx).Load(ctx, obj.ReviewID)
+}
+
+// Reviews is the resolver for the reviews field.
+func (r *userResolver) Reviews(ctx context.Context, obj *generated.User, filter *generated.ReviewFilter, orderBy *generated.ReviewOrder) ([]*models.Review, error) {
+	query, err := newReviewListQuery(obj.ID, 0, filter, orderBy)
+	if err != nil {
+		return nil, err
+	}
+	return CtxUserReviewListProvider(ctx).Load(ctx, query)
+}
+
+// ReviewsConnection is the resolver for the reviewsConnection field.
+func (r *userResolver) ReviewsConnection(ctx context.Context, obj *generated.User, first *int, after *string, last *int, before *string) (*generated.ReviewConnection, error) {
+	return fetchReviewsConnection(ctx, "/users/"+url.PathEscape(obj.ID)+"/reviews", first, after, last, before)
+}
+
+// Answer returns generated.AnswerResolver implementation.
+func (r *Resolver) Answer() generated.AnswerResolver { return &answerResolver{r} }
+
+// Mutation returns generated.MutationResolver implementation.
+func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
+
+// OfficialResponse returns generated.OfficialResponseResolver implementation.
+func (r *Resolver) OfficialResponse() generated.OfficialResponseResolver {
+	return &officialResponseResolver{r}
+}
+
+// Product returns generated.ProductResolver implementation.
+func (r *Resolver) Product() generated.ProductResolver { return &productResolver{r} }
+
+// Query returns generated.QueryResolver implementation.
+func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
+
+// Question returns generated.QuestionResolver implementation.
+func (r *Resolver) Question() generated.QuestionResolver { return &questionResolver{r} }
+
 // Review returns generated.ReviewResolver implementation.
 func (r *Resolver) Review() generated.ReviewResolver { return &reviewResolver{r} }
 
+// ReviewReport returns generated.ReviewReportResolver implementation.
+func (r *Resolver) ReviewReport() generated.ReviewReportResolver { return &reviewReportResolver{r} }
+
+// ReviewRevision returns generated.ReviewRevisionResolver implementation.
+func (r *Resolver) ReviewRevision() generated.ReviewRevisionResolver {
+	return &reviewRevisionResolver{r}
+}
+
+// SummarySentence returns generated.SummarySentenceResolver implementation.
+func (r *Resolver) SummarySentence() generated.SummarySentenceResolver {
+	return &summarySentenceResolver{r}
+}
+
+// User returns generated.UserResolver implementation.
+func (r *Resolver) User() generated.UserResolver { return &userResolver{r} }
+
+type answerResolver struct{ *Resolver }
+type mutationResolver struct{ *Resolver }
+type officialResponseResolver struct{ *Resolver }
+type productResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type questionResolver struct{ *Resolver }
 type reviewResolver struct{ *Resolver }
+type reviewReportResolver struct{ *Resolver }
+type reviewRevisionResolver struct{ *Resolver }
+type summarySentenceResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
@@ -0,0 +1,76 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"product-reviews/internal/generated"
+	"product-reviews/internal/review/models"
+)
+
+// searchHit is a review returned by the reviews API's full-text search.
+type searchHit struct {
+	models.Review
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+// fetchReviewSearch loads one page of full-text search results, fetching one hit
+// beyond the page size to tell whether another page follows.
+func fetchReviewSearch(ctx context.Context, query string, productID *string, minRating *int, first *int, after *string) (*generated.ReviewSearchConnection, error) {
+	size := defaultPageSize
+	if first != nil {
+		size = *first
+	}
+	if size < 0 {
+		return nil, errors.New("page size must not be negative")
+	}
+	if size > maxPageSize {
+		size = maxPageSize
+	}
+
+	q := url.Values{}
+	q.Set("q", query)
+	q.Set("first", strconv.Itoa(size+1))
+	if productID != nil {
+		q.Set("productId", *productID)
+	}
+	if minRating != nil {
+		q.Set("minRating", strconv.Itoa(*minRating))
+	}
+	if after != nil {
+		q.Set("after", *after)
+	}
+
+	var hits []*searchHit
+	if err := callReviewsAPI(ctx, http.MethodGet, "/reviews/search?"+q.Encode(), nil, &hits); err != nil {
+		return nil, err
+	}
+
+	pageInfo := &generated.PageInfo{
+		HasPreviousPage: after != nil,
+		HasNextPage:     len(hits) > size,
+	}
+	if pageInfo.HasNextPage {
+		hits = hits[:size]
+	}
+
+	edges := make([]*generated.ReviewSearchEdge, 0, len(hits))
+	for _, hit := range hits {
+		edges = append(edges, &generated.ReviewSearchEdge{
+			Cursor:  hit.Cursor,
+			Node:    &hit.Review,
+			Rank:    hit.Rank,
+			Snippet: hit.Snippet,
+		})
+	}
+	if len(edges) > 0 {
+		pageInfo.StartCursor = &edges[0].Cursor
+		pageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+
+	return &generated.ReviewSearchConnection{Edges: edges, PageInfo: pageInfo}, nil
+}
@@ -0,0 +1,116 @@
+package resolvers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"product-reviews/internal/review/models"
+	"slices"
+	"strings"
+)
+
+const ViewerKey CtxKey = "viewer"
+
+const (
+	// RoleAdmin grants access to moderation queries and mutations.
+	RoleAdmin = "admin"
+	// RoleMerchant allows publishing official responses to reviews.
+	RoleMerchant = "merchant"
+)
+
+// Viewer is the end user on whose behalf the gateway forwarded the request.
+type Viewer struct {
+	UserID string
+	Roles  []string
+}
+
+func (v *Viewer) HasRole(role string) bool {
+	return slices.Contains(v.Roles, role)
+}
+
+var (
+	errUnauthenticated = errors.New("authentication required")
+	errForbidden       = errors.New("not allowed")
+)
+
+// gatewaySecret is shared with the gateway through GATEWAY_SECRET. Identity headers are
+// only trusted on requests that carry it in X-Gateway-Secret, so callers that reach the
+// subgraph directly can't claim to be another user or an admin. Without a secret every
+// request is anonymous.
+var gatewaySecret = os.Getenv("GATEWAY_SECRET")
+
+func fromGateway(r *http.Request) bool {
+	if gatewaySecret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Gateway-Secret")), []byte(gatewaySecret)) == 1
+}
+
+// ViewerMiddleware reads the caller identity forwarded by the gateway in the X-User-Id
+// and X-User-Roles headers and stores it in the request context. Requests that don't come
+// from the gateway are treated as anonymous.
+func ViewerMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if userID := r.Header.Get("X-User-Id"); userID != "" && fromGateway(r) {
+			viewer := &Viewer{UserID: userID}
+			if roles := r.Header.Get("X-User-Roles"); roles != "" {
+				viewer.Roles = strings.Split(roles, ",")
+			}
+			ctx = context.WithValue(ctx, ViewerKey, viewer)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// CtxViewer returns the caller for the current request, or an error when the request is
+// anonymous.
+func CtxViewer(ctx context.Context) (*Viewer, error) {
+	if viewer, ok := ctx.Value(ViewerKey).(*Viewer); ok {
+		return viewer, nil
+	}
+	return nil, errUnauthenticated
+}
+
+// CtxAdmin returns the caller for the current request if it has the admin role.
+func CtxAdmin(ctx context.Context) (*Viewer, error) {
+	return ctxViewerWithRole(ctx, RoleAdmin)
+}
+
+// CtxMerchant returns the caller for the current request if it may respond to reviews
+// on behalf of a merchant.
+func CtxMerchant(ctx context.Context) (*Viewer, error) {
+	return ctxViewerWithRole(ctx, RoleMerchant, RoleAdmin)
+}
+
+func ctxViewerWithRole(ctx context.Context, roles ...string) (*Viewer, error) {
+	viewer, err := CtxViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.ContainsFunc(roles, viewer.HasRole) {
+		return nil, errForbidden
+	}
+	return viewer, nil
+}
+
+// reviewOfViewer loads a review the caller wrote, or any review if the caller is an
+// admin. Reviews awaiting moderation are included so authors can fix them.
+func reviewOfViewer(ctx context.Context, id string) (*models.Review, error) {
+	viewer, err := CtxViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var review models.Review
+	if err := callReviewsAPI(ctx, http.MethodGet, "/reviews/"+url.PathEscape(id), nil, &review); err != nil {
+		return nil, err
+	}
+	if review.UserID != viewer.UserID && !viewer.HasRole(RoleAdmin) {
+		return nil, errForbidden
+	}
+	return &review, nil
+}
@@ -0,0 +1,16 @@
+package models
+
+// ReviewAspect maps to the ReviewAspect GraphQL type
+type ReviewAspect struct {
+	Aspect         string         `json:"aspect"`
+	MentionCount   int            `json:"mentionCount"`
+	AverageRating  float64        `json:"averageRating"`
+	SentimentScore float64        `json:"sentimentScore"`
+	Sentiment      SentimentLabel `json:"sentiment"`
+}
+
+// ProductAspects holds the aspects the reviews API found in a product's reviews
+type ProductAspects struct {
+	ProductID string          `json:"productId"`
+	Aspects   []*ReviewAspect `json:"aspects"`
+}
@@ -0,0 +1,25 @@
+package models
+
+// Duplicate records that a review near-duplicates the original of its cluster
+type Duplicate struct {
+	ReviewID   string  `json:"reviewId"`
+	OriginalID string  `json:"originalId"`
+	Similarity float64 `json:"similarity"`
+}
+
+// DuplicateCluster maps to the DuplicateReviewCluster GraphQL type. Reviews are
+// ordered oldest first, so the original is always the first review.
+type DuplicateCluster struct {
+	ID               string    `json:"id"`
+	Size             int       `json:"size"`
+	DistinctUsers    int       `json:"distinctUsers"`
+	DistinctProducts int       `json:"distinctProducts"`
+	Reviews          []*Review `json:"reviews"`
+}
+
+func (c *DuplicateCluster) Original() *Review {
+	if len(c.Reviews) == 0 {
+		return nil
+	}
+	return c.Reviews[0]
+}
@@ -0,0 +1,8 @@
+package models
+
+// VerifiedPurchase holds the orders API's answer to whether a user bought a product
+type VerifiedPurchase struct {
+	UserID    string `json:"userId"`
+	ProductID string `json:"productId"`
+	Verified  bool   `json:"verified"`
+}
@@ -0,0 +1,30 @@
+package models
+
+// Question maps to the Question GraphQL type
+type Question struct {
+	ID               string       `json:"id"`
+	ProductID        string       `json:"productId"`
+	UserID           string       `json:"userId"`
+	Body             string       `json:"body"`
+	CreatedAt        string       `json:"createdAt"`
+	Status           ReviewStatus `json:"status"`
+	AnswerCount      int          `json:"answerCount"`
+	AcceptedAnswerID string       `json:"acceptedAnswerId,omitempty"`
+	Cursor           string       `json:"cursor,omitempty"`
+}
+
+func (Question) IsEntity() {}
+
+// Answer maps to the Answer GraphQL type
+type Answer struct {
+	ID          string       `json:"id"`
+	QuestionID  string       `json:"questionId"`
+	UserID      string       `json:"userId"`
+	Body        string       `json:"body"`
+	CreatedAt   string       `json:"createdAt"`
+	Status      ReviewStatus `json:"status"`
+	Accepted    bool         `json:"accepted"`
+	UpvoteCount int          `json:"upvoteCount"`
+}
+
+func (Answer) IsEntity() {}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ReportReason maps to the ReportReason GraphQL enum
+type ReportReason string
+
+const (
+	ReportReasonSpam      ReportReason = "SPAM"
+	ReportReasonOffensive ReportReason = "OFFENSIVE"
+	ReportReasonOffTopic  ReportReason = "OFF_TOPIC"
+	ReportReasonFake      ReportReason = "FAKE"
+)
+
+func (r ReportReason) IsValid() bool {
+	switch r {
+	case ReportReasonSpam, ReportReasonOffensive, ReportReasonOffTopic, ReportReasonFake:
+		return true
+	}
+	return false
+}
+
+func (r *ReportReason) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*r = ReportReason(str)
+	if !r.IsValid() {
+		return fmt.Errorf("%s is not a valid ReportReason", str)
+	}
+	return nil
+}
+
+func (r ReportReason) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(string(r)))
+}
+
+// ReportStatus maps to the ReportStatus GraphQL enum
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "OPEN"
+	ReportStatusResolved  ReportStatus = "RESOLVED"
+	ReportStatusDismissed ReportStatus = "DISMISSED"
+)
+
+func (s ReportStatus) IsValid() bool {
+	switch s {
+	case ReportStatusOpen, ReportStatusResolved, ReportStatusDismissed:
+		return true
+	}
+	return false
+}
+
+func (s *ReportStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*s = ReportStatus(str)
+	if !s.IsValid() {
+		return fmt.Errorf("%s is not a valid ReportStatus", str)
+	}
+	return nil
+}
+
+func (s ReportStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(string(s)))
+}
+
+// ReviewReport maps to the ReviewReport GraphQL type
+type ReviewReport struct {
+	ID             string       `json:"id"`
+	ReviewID       string       `json:"reviewId"`
+	ReporterID     string       `json:"reporterId"`
+	Reason         ReportReason `json:"reason"`
+	Details        *string      `json:"details,omitempty"`
+	Status         ReportStatus `json:"status"`
+	CreatedAt      string       `json:"createdAt"`
+	ResolvedByID   string       `json:"resolvedBy,omitempty"`
+	ResolvedAt     *string      `json:"resolvedAt,omitempty"`
+	ResolutionNote *string      `json:"resolutionNote,omitempty"`
+	Review         *Review      `json:"review"`
+}
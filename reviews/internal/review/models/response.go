@@ -0,0 +1,10 @@
+package models
+
+// OfficialResponse maps to the OfficialResponse GraphQL type
+type OfficialResponse struct {
+	ReviewID    string  `json:"reviewId"`
+	ResponderID string  `json:"responderId"`
+	Body        string  `json:"body"`
+	RespondedAt string  `json:"respondedAt"`
+	UpdatedAt   *string `json:"updatedAt,omitempty"`
+}
@@ -2,12 +2,19 @@ package models
 
 // Review maps to the Review GraphQL type
 type Review struct {
-	ID        string `json:"id"`
-	ProductID string `json:"productId"`
-	UserID    string `json:"userId"`
-	Body      string `json:"body"`
-	Rating    int    `json:"rating"`
-	CreatedAt string `json:"createdAt"` // In production, consider using time.Time
+	ID        string  `json:"id"`
+	ProductID string  `json:"productId"`
+	UserID    string  `json:"userId"`
+	Body      string  `json:"body"`
+	Rating    int     `json:"rating"`
+	CreatedAt string  `json:"createdAt"` // In production, consider using time.Time
+	EditedAt  *string `json:"editedAt,omitempty"`
+	Cursor    string  `json:"cursor,omitempty"`
+
+	Status           ReviewStatus `json:"status"`
+	ModerationReason *string      `json:"moderationReason,omitempty"`
+
+	Sentiment *ReviewSentiment `json:"sentiment,omitempty"`
 }
 
 func (Review) IsEntity() {}
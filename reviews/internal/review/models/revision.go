@@ -0,0 +1,11 @@
+package models
+
+// Revision maps to the ReviewRevision GraphQL type
+type Revision struct {
+	ReviewID string `json:"reviewId"`
+	Revision int    `json:"revision"`
+	Body     string `json:"body"`
+	Rating   int    `json:"rating"`
+	EditorID string `json:"editorId"`
+	EditedAt string `json:"editedAt"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// SentimentLabel maps to the SentimentLabel GraphQL enum
+type SentimentLabel string
+
+const (
+	SentimentLabelPositive SentimentLabel = "POSITIVE"
+	SentimentLabelNeutral  SentimentLabel = "NEUTRAL"
+	SentimentLabelNegative SentimentLabel = "NEGATIVE"
+)
+
+func (l SentimentLabel) IsValid() bool {
+	switch l {
+	case SentimentLabelPositive, SentimentLabelNeutral, SentimentLabelNegative:
+		return true
+	}
+	return false
+}
+
+func (l *SentimentLabel) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*l = SentimentLabel(str)
+	if !l.IsValid() {
+		return fmt.Errorf("%s is not a valid SentimentLabel", str)
+	}
+	return nil
+}
+
+func (l SentimentLabel) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(string(l)))
+}
+
+// ReviewSentiment maps to the ReviewSentiment GraphQL type
+type ReviewSentiment struct {
+	Score          float64        `json:"score"`
+	Label          SentimentLabel `json:"label"`
+	RatingMismatch bool           `json:"ratingMismatch"`
+}
+
+// SentimentBreakdown maps to the SentimentBreakdown GraphQL type
+type SentimentBreakdown struct {
+	Positive     int      `json:"positive"`
+	Neutral      int      `json:"neutral"`
+	Negative     int      `json:"negative"`
+	AverageScore *float64 `json:"averageScore"`
+}
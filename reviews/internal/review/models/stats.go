@@ -0,0 +1,17 @@
+package models
+
+// RatingBucket maps to the RatingBucket GraphQL type
+type RatingBucket struct {
+	Stars int `json:"stars"`
+	Count int `json:"count"`
+}
+
+// ProductRatingStats holds the rating aggregates the reviews API computes for a product
+type ProductRatingStats struct {
+	ProductID     string          `json:"productId"`
+	AverageRating *float64        `json:"averageRating"`
+	ReviewCount   int             `json:"reviewCount"`
+	Histogram     []*RatingBucket `json:"histogram"`
+
+	Sentiment SentimentBreakdown `json:"sentiment"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ReviewStatus maps to the ReviewStatus GraphQL enum
+type ReviewStatus string
+
+const (
+	ReviewStatusPending  ReviewStatus = "PENDING"
+	ReviewStatusApproved ReviewStatus = "APPROVED"
+	ReviewStatusRejected ReviewStatus = "REJECTED"
+	ReviewStatusHidden   ReviewStatus = "HIDDEN"
+)
+
+func (s ReviewStatus) IsValid() bool {
+	switch s {
+	case ReviewStatusPending, ReviewStatusApproved, ReviewStatusRejected, ReviewStatusHidden:
+		return true
+	}
+	return false
+}
+
+func (s *ReviewStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*s = ReviewStatus(str)
+	if !s.IsValid() {
+		return fmt.Errorf("%s is not a valid ReviewStatus", str)
+	}
+	return nil
+}
+
+func (s ReviewStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(string(s)))
+}
@@ -0,0 +1,16 @@
+package models
+
+// ReviewSummary maps to the ReviewSummary GraphQL type
+type ReviewSummary struct {
+	ProductID   string             `json:"productId"`
+	Likes       []*SummarySentence `json:"likes"`
+	Complaints  []*SummarySentence `json:"complaints"`
+	ReviewCount int                `json:"reviewCount"`
+	ComputedAt  string             `json:"computedAt"`
+}
+
+// SummarySentence maps to the SummarySentence GraphQL type
+type SummarySentence struct {
+	Text     string `json:"text"`
+	ReviewID string `json:"reviewId"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// VoteCounts holds the helpful/not-helpful vote totals for a review
+type VoteCounts struct {
+	ReviewID       string `json:"reviewId"`
+	HelpfulCount   int    `json:"helpfulCount"`
+	UnhelpfulCount int    `json:"unhelpfulCount"`
+}
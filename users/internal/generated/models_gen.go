@@ -2,5 +2,34 @@
 
 package generated
 
+import (
+	"users/internal/user/models"
+)
+
+// The tokens of a session. Send the access token to the gateway as
+// `Authorization: Bearer <accessToken>`. When it expires, after expiresIn seconds, trade
+// the refresh token for a new pair with refreshToken. Each refresh token works once.
+type AuthPayload struct {
+	AccessToken  string       `json:"accessToken"`
+	RefreshToken string       `json:"refreshToken"`
+	ExpiresIn    int          `json:"expiresIn"`
+	User         *models.User `json:"user"`
+}
+
+// Usernames are 3 to 30 letters, digits, underscores and hyphens, start with a letter and
+// end with a letter or digit. They are unique regardless of case, and some names are
+// reserved. Violations fail with INVALID_USERNAME, USERNAME_RESERVED or USERNAME_TAKEN as
+// the code in the error extensions, and unknown users with USER_NOT_FOUND. Passwords that
+// are too short or too long fail with WEAK_PASSWORD, failed logins with
+// INVALID_CREDENTIALS and unusable refresh tokens with INVALID_REFRESH_TOKEN.
+type Mutation struct {
+}
+
 type Query struct {
 }
+
+type RegisterUserInput struct {
+	Username string `json:"username"`
+	// 8 to 128 characters.
+	Password string `json:"password"`
+}
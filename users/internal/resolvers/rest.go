@@ -0,0 +1,87 @@
+package resolvers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const usersAPI = "http://localhost:8080"
+
+// CodeUserNotFound is reported in the error extensions when a mutation names an
+// unknown user. Username policy violations carry the code given by the users API.
+const CodeUserNotFound = "USER_NOT_FOUND"
+
+// apiError turns a failed API response into an error. Structured errors, such as a
+// username that is already taken, keep their code and details as GraphQL error
+// extensions so clients can act on them, and a 404 becomes a USER_NOT_FOUND error.
+func apiError(status int, msg []byte) error {
+	var structured map[string]any
+	if json.Unmarshal(msg, &structured) == nil {
+		if text, ok := structured["error"].(string); ok {
+			delete(structured, "error")
+			return &gqlerror.Error{Message: text, Extensions: structured}
+		}
+	}
+	if status == http.StatusNotFound {
+		return &gqlerror.Error{Message: "user not found", Extensions: map[string]any{"code": CodeUserNotFound}}
+	}
+	return fmt.Errorf("users API: %s", strings.TrimSpace(string(msg)))
+}
+
+// isUserNotFound reports whether err is the USER_NOT_FOUND error made by apiError.
+func isUserNotFound(err error) bool {
+	var gqlErr *gqlerror.Error
+	return errors.As(err, &gqlErr) && gqlErr.Extensions["code"] == CodeUserNotFound
+}
+
+// callUsersAPI sends a request with an optional JSON payload to the users REST API and
+// decodes the JSON response into out. Non-2xx responses are returned as errors.
+func callUsersAPI(ctx context.Context, method, path string, payload any, out any) error {
+	var body io.Reader
+	if payload != nil {
+		buf, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %v", err)
+		}
+		body = bytes.NewReader(buf)
+	}
+
+	url := usersAPI + path
+	fmt.Printf("[Users Subgraph] Making REST call to: %s %s\n", method, url)
+	GetApiCounter(ctx).Increment(path)
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %v", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call users API: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(resp.Body)
+		return apiError(resp.StatusCode, msg)
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %v", err)
+	}
+	return nil
+}
@@ -7,16 +7,115 @@ package resolvers
 
 import (
 	"context"
+	"net/http"
+	"net/url"
 	"users/internal/generated"
 	"users/internal/user/models"
 )
 
+// RegisterUser is the resolver for the registerUser field.
+func (r *mutationResolver) RegisterUser(ctx context.Context, input generated.RegisterUserInput) (*models.User, error) {
+	var created models.User
+	if err := callUsersAPI(ctx, http.MethodPost, "/users", input, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// Login is the resolver for the login field.
+func (r *mutationResolver) Login(ctx context.Context, username string, password string) (*generated.AuthPayload, error) {
+	credentials := map[string]string{"username": username, "password": password}
+	var payload generated.AuthPayload
+	if err := callUsersAPI(ctx, http.MethodPost, "/auth/login", credentials, &payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+// RefreshToken is the resolver for the refreshToken field.
+func (r *mutationResolver) RefreshToken(ctx context.Context, refreshToken string) (*generated.AuthPayload, error) {
+	var payload generated.AuthPayload
+	if err := callUsersAPI(ctx, http.MethodPost, "/auth/refresh", map[string]string{"refreshToken": refreshToken}, &payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+// Logout is the resolver for the logout field.
+func (r *mutationResolver) Logout(ctx context.Context, refreshToken string) (bool, error) {
+	if err := callUsersAPI(ctx, http.MethodPost, "/auth/revoke", map[string]string{"refreshToken": refreshToken}, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ChangePassword is the resolver for the changePassword field.
+func (r *mutationResolver) ChangePassword(ctx context.Context, currentPassword string, newPassword string) (bool, error) {
+	viewer, err := CtxViewer(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	passwords := map[string]string{"currentPassword": currentPassword, "password": newPassword}
+	if err := callUsersAPI(ctx, http.MethodPut, "/users/"+url.PathEscape(viewer.UserID)+"/password", passwords, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// UpdateUsername is the resolver for the updateUsername field.
+func (r *mutationResolver) UpdateUsername(ctx context.Context, id string, username string) (*models.User, error) {
+	if _, err := CtxAccountOf(ctx, id); err != nil {
+		return nil, err
+	}
+
+	user := models.User{Username: username}
+	var updated models.User
+	if err := callUsersAPI(ctx, http.MethodPut, "/users/"+url.PathEscape(id), user, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteUser is the resolver for the deleteUser field.
+func (r *mutationResolver) DeleteUser(ctx context.Context, id string) (*models.User, error) {
+	if _, err := CtxAccountOf(ctx, id); err != nil {
+		return nil, err
+	}
+
+	// Load the user first so the deleted entity can be handed back to the client.
+	var deleted models.User
+	if err := callUsersAPI(ctx, http.MethodGet, "/users/"+url.PathEscape(id), nil, &deleted); err != nil {
+		return nil, err
+	}
+	if err := callUsersAPI(ctx, http.MethodDelete, "/users/"+url.PathEscape(id), nil, nil); err != nil {
+		return nil, err
+	}
+	return &deleted, nil
+}
+
 // User is the resolver for the user field.
 func (r *queryResolver) User(ctx context.Context, id string) (*models.User, error) {
 	return CtxLoadProvider(ctx).Load(ctx, id)
 }
 
+// UserByUsername is the resolver for the userByUsername field.
+func (r *queryResolver) UserByUsername(ctx context.Context, username string) (*models.User, error) {
+	var user models.User
+	err := callUsersAPI(ctx, http.MethodGet, "/users/by-username/"+url.PathEscape(username), nil, &user)
+	if isUserNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Mutation returns generated.MutationResolver implementation.
+func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
+
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }